@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/minicli"
+)
+
+type bisectCmd struct {
+	modpath *string // injected by main command
+	cfg     *config // injected by main command
+	good    string
+	bad     string
+	export  string
+}
+
+func newBisectCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &bisectCmd{modpath: modpath, cfg: cfg}
+}
+
+func (bc *bisectCmd) SetFlags(flags *flag.FlagSet) {
+	flags.StringVar(&bc.good, "good", "", "commit or tag without the breaking change")
+	flags.StringVar(&bc.bad, "bad", "HEAD", "commit or tag with the breaking change")
+	flags.StringVar(&bc.export, "export", "",
+		"search for a change to a specific export, such as example.com/mod/pkg.Func (default any breaking change)")
+}
+
+func (bc *bisectCmd) Exec(args []string) error {
+	modpath := *bc.modpath
+	if bc.good == "" {
+		return fmt.Errorf("a good commit must be specified with -good")
+	}
+
+	// list commits after good up to and including bad, from oldest to newest, following only the
+	// first parent of merges so that the commits form a line that may be searched in order
+	revlist, err := gitOutput(bc.cfg.ctx, modpath, "rev-list", "--reverse", "--first-parent",
+		"--ancestry-path", versionTag(modpath, bc.good)+".."+versionTag(modpath, bc.bad))
+	if err != nil {
+		return err
+	} else if revlist == "" {
+		return fmt.Errorf("no commits found from %s to %s", bc.good, bc.bad)
+	}
+	commits := strings.Split(revlist, "\n")
+
+	rp, err := gover.NewRevisionParser(bc.cfg.ctx, modpath, bc.cfg.options()...)
+	if err != nil {
+		return err
+	}
+	defer rp.Close()
+
+	goodModule, err := rp.Parse(bc.cfg.ctx, bc.good)
+	if err != nil {
+		return err
+	}
+
+	isBad := func(commit string) (bool, error) {
+		module, err := rp.Parse(bc.cfg.ctx, commit)
+		if err != nil {
+			return false, err
+		}
+		moduleDifference := modface.Diff(goodModule, module)
+		if bc.export == "" {
+			return moduleDifference.Breaking(), nil
+		}
+		return breaksExport(moduleDifference, bc.export), nil
+	}
+
+	// verify that the change is present at all
+	if bad, err := isBad(commits[len(commits)-1]); err != nil {
+		return err
+	} else if !bad {
+		return fmt.Errorf("no breaking change found between %s and %s", bc.good, bc.bad)
+	}
+
+	// binary search for the first bad commit
+	lo, hi := 0, len(commits)-1
+	for lo < hi {
+		mid := (lo + hi) / 2
+		bad, err := isBad(commits[mid])
+		if err != nil {
+			return err
+		}
+		if bad {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	summary, err := gitOutput(bc.cfg.ctx, modpath, "log", "-1",
+		"--format=commit %H%nAuthor: %an <%ae>%nDate:   %ad%n%n    %s", commits[lo])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s is the first breaking commit\n", commits[lo])
+	fmt.Println(summary)
+
+	return nil
+}
+
+// breaksExport returns true if an export, qualified by its package path, was removed or changed.
+func breaksExport(md *modface.ModuleDifference, export string) bool {
+	for pkgname, pkgface := range md.PackageRemovals {
+		for _, face := range pkgface {
+			if qualifiedName(pkgname, face) == export {
+				return true
+			}
+		}
+	}
+	for pkgname, pkgchanges := range md.PackageChanges {
+		for _, face := range pkgchanges.Removals {
+			if qualifiedName(pkgname, face) == export {
+				return true
+			}
+		}
+		for _, facediff := range pkgchanges.Changes {
+			if qualifiedName(pkgname, facediff.Old) == export {
+				return true
+			}
+		}
+	}
+	return false
+}
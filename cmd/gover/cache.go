@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/minicli"
+)
+
+type cacheCleanCmd struct {
+	stale bool
+}
+
+func newCacheCleanCmd() minicli.CmdImpl {
+	return &cacheCleanCmd{}
+}
+
+func (cc *cacheCleanCmd) SetFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&cc.stale, "stale", false, "only remove modules stored for other schema versions")
+}
+
+func (cc *cacheCleanCmd) Exec(args []string) error {
+	cache, err := gover.DefaultCache()
+	if err != nil {
+		return err
+	} else if cache == nil {
+		return fmt.Errorf("module cache is disabled by GOVERCACHE=off")
+	} else if cc.stale {
+		return cache.CleanStale()
+	}
+	return cache.Clean()
+}
+
+type cacheInfoCmd struct{}
+
+func newCacheInfoCmd() minicli.CmdImpl {
+	return &cacheInfoCmd{}
+}
+
+func (cc *cacheInfoCmd) SetFlags(flags *flag.FlagSet) {}
+
+func (cc *cacheInfoCmd) Exec(args []string) error {
+	cache, err := gover.DefaultCache()
+	if err != nil {
+		return err
+	} else if cache == nil {
+		fmt.Println("module cache is disabled by GOVERCACHE=off")
+		return nil
+	}
+
+	info, err := cache.Info()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("directory: %s\n", info.Dir)
+	fmt.Printf("schema:    v%d\n", modface.SchemaVersion)
+	fmt.Printf("modules:   %d\n", info.Entries)
+	fmt.Printf("size:      %s\n", formatBytes(info.Size))
+	if info.Stale > 0 {
+		fmt.Printf("stale:     %d modules from other schema versions (remove with cache clean -stale)\n",
+			info.Stale)
+	}
+	return nil
+}
+
+// formatBytes returns a human-readable size.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
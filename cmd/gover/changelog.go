@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/minicli"
+)
+
+const changelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+type changelogCmd struct {
+	modpath *string // injected by main command
+	cfg     *config // injected by main command
+	from    string
+	version string
+	commits bool
+	prepend string
+}
+
+func newChangelogCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &changelogCmd{modpath: modpath, cfg: cfg}
+}
+
+func (cc *changelogCmd) SetFlags(flags *flag.FlagSet) {
+	flags.StringVar(&cc.from, "from", "", "commit or tag of the previous release (default latest version)")
+	flags.StringVar(&cc.version, "version", "", "version to title the section with (default Unreleased)")
+	flags.BoolVar(&cc.commits, "commits", false, "include commit subjects since the previous release")
+	flags.StringVar(&cc.prepend, "prepend", "", "prepend the section to a changelog file instead of printing it")
+}
+
+func (cc *changelogCmd) Exec(args []string) error {
+	modpath := *cc.modpath
+
+	from := cc.from
+	if from == "" {
+		versions, err := gover.Versions(cc.cfg.ctx, modpath)
+		if err != nil {
+			return err
+		} else if len(versions) == 0 {
+			return fmt.Errorf("no versions found, specify a commit with -from")
+		}
+		from = versions[len(versions)-1]
+	}
+
+	moduleDifference, err := gover.Diff(cc.cfg.ctx, modpath, from, cc.cfg.options()...)
+	if err != nil {
+		return err
+	}
+
+	var subjects []string
+	if cc.commits {
+		log, err := gitOutput(cc.cfg.ctx, modpath, "log", "--format=%s",
+			versionTag(modpath, from)+"..HEAD")
+		if err != nil {
+			return err
+		}
+		if log != "" {
+			subjects = strings.Split(log, "\n")
+		}
+	}
+
+	section := changelogSection(moduleDifference, cc.version, time.Now(), subjects)
+
+	if cc.prepend == "" {
+		fmt.Print(section)
+		return nil
+	}
+	return prependChangelog(cc.prepend, section)
+}
+
+// changelogSection renders a Keep a Changelog release section for a module difference.
+func changelogSection(md *modface.ModuleDifference, version string, date time.Time,
+	subjects []string) string {
+
+	var breaking, added, changed, deprecated []string
+
+	// changes to unstable packages are not breaking
+	breakingOrChanged := func(pkgname, entry string) {
+		if md.Unstable[pkgname] {
+			changed = append(changed, entry)
+		} else {
+			breaking = append(breaking, entry)
+		}
+	}
+
+	if !md.ModPathsMatch {
+		breaking = append(breaking, fmt.Sprintf("Module path changed from `%s` to `%s`",
+			md.OldModPath, md.ModPath))
+	}
+	for _, pkgname := range sortedPackageNames(md.PackageRemovals) {
+		breakingOrChanged(pkgname, fmt.Sprintf("Removed package `%s`", pkgname))
+	}
+	for _, pkgname := range sortedPackageNames(md.PackageAdditions) {
+		added = append(added, fmt.Sprintf("Package `%s`", pkgname))
+	}
+
+	pkgnames := []string{}
+	for pkgname := range md.PackageChanges {
+		pkgnames = append(pkgnames, pkgname)
+	}
+	sort.Strings(pkgnames)
+
+	for _, pkgname := range pkgnames {
+		pkgchanges := md.PackageChanges[pkgname]
+		for _, face := range sortedExports(pkgchanges.Removals) {
+			breakingOrChanged(pkgname, fmt.Sprintf("Removed `%s` from `%s`", face, pkgname))
+		}
+		for _, face := range sortedExports(pkgchanges.Additions) {
+			added = append(added, fmt.Sprintf("`%s` to `%s`", face, pkgname))
+		}
+		for _, facediff := range sortedExportDifferences(pkgchanges.Changes) {
+			entry := fmt.Sprintf("Changed `%s` in `%s`\n  - old: `%s`\n  - new: `%s`",
+				exportName(facediff.New), pkgname, facediff.Old, facediff.New)
+			breakingOrChanged(pkgname, entry)
+		}
+		for _, facediff := range sortedExportDifferences(pkgchanges.Deprecations) {
+			deprecated = append(deprecated, fmt.Sprintf("`%s` in `%s`", facediff.New, pkgname))
+		}
+	}
+
+	var sb strings.Builder
+	if version == "" {
+		sb.WriteString("## [Unreleased]\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("## [%s] - %s\n", version, date.Format("2006-01-02")))
+	}
+
+	writeGroup := func(title string, entries []string) {
+		if len(entries) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n### %s\n\n", title))
+		for _, entry := range entries {
+			sb.WriteString(fmt.Sprintf("- %s\n", entry))
+		}
+	}
+	writeGroup("Breaking changes", breaking)
+	writeGroup("Added", added)
+	writeGroup("Changed", append(changed, subjects...))
+	writeGroup("Deprecated", deprecated)
+
+	return sb.String()
+}
+
+// prependChangelog inserts section above the most recent release of the changelog at path.
+// An existing Unreleased section is merged into section, as a changelog has only one.
+// If the changelog does not exist, it is created.
+func prependChangelog(path, section string) error {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		content = []byte(changelogHeader)
+	} else if err != nil {
+		return err
+	}
+
+	// insert before first release heading, or at end of file if there are none
+	insertAt := len(content)
+	if bytes.HasPrefix(content, []byte("## ")) {
+		insertAt = 0
+	} else if i := bytes.Index(content, []byte("\n## ")); i >= 0 {
+		insertAt = i + 1
+	}
+
+	// replace the Unreleased section with the merged section
+	rest := content[insertAt:]
+	if isUnreleasedHeading(rest) {
+		end := len(rest)
+		if i := bytes.Index(rest, []byte("\n## ")); i >= 0 {
+			end = i + 1
+		}
+		section = mergeChangelogSections(string(rest[:end]), section)
+		rest = rest[end:]
+	}
+
+	var buf bytes.Buffer
+	buf.Write(content[:insertAt])
+	if insertAt > 0 && !bytes.HasSuffix(content[:insertAt], []byte("\n\n")) {
+		buf.WriteString("\n")
+	}
+	buf.WriteString(section)
+	if len(rest) > 0 {
+		buf.WriteString("\n")
+		buf.Write(rest)
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// isUnreleasedHeading returns true if content starts with the heading of an Unreleased section.
+func isUnreleasedHeading(content []byte) bool {
+	line := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		line = content[:i]
+	}
+	title := strings.TrimSpace(strings.TrimPrefix(string(line), "## "))
+	return bytes.HasPrefix(content, []byte("## ")) &&
+		strings.EqualFold(strings.Trim(title, "[]"), "unreleased")
+}
+
+// changelogGroup is a group of entries in a changelog section, such as Added.
+type changelogGroup struct {
+	Title   string
+	Entries []string
+}
+
+// parseChangelogSection splits a changelog section into its heading, any text before its first
+// group, and its groups.
+func parseChangelogSection(section string) (heading, preamble string, groups []*changelogGroup) {
+	lines := strings.Split(strings.TrimRight(section, "\n"), "\n")
+	heading, lines = lines[0], lines[1:]
+
+	var pre []string
+	var group *changelogGroup
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "### "):
+			group = &changelogGroup{Title: strings.TrimSpace(line[4:])}
+			groups = append(groups, group)
+		case group == nil:
+			pre = append(pre, line)
+		case strings.HasPrefix(line, "- "):
+			group.Entries = append(group.Entries, line)
+		case strings.TrimSpace(line) == "":
+			// blank lines separate groups
+		case len(group.Entries) > 0:
+			// continuation of a multi-line entry
+			group.Entries[len(group.Entries)-1] += "\n" + line
+		default:
+			group.Entries = append(group.Entries, line)
+		}
+	}
+
+	return heading, strings.Trim(strings.Join(pre, "\n"), "\n"), groups
+}
+
+// mergeChangelogSections merges the entries of an existing section into a generated section.
+// The heading of the generated section is kept, existing entries keep their order, and generated
+// entries which are not present yet are added after them.
+func mergeChangelogSections(existing, generated string) string {
+	_, preamble, groups := parseChangelogSection(existing)
+	heading, _, newGroups := parseChangelogSection(generated)
+
+	byTitle := make(map[string]*changelogGroup)
+	for _, group := range groups {
+		byTitle[group.Title] = group
+	}
+	for _, newGroup := range newGroups {
+		group, found := byTitle[newGroup.Title]
+		if !found {
+			group = &changelogGroup{Title: newGroup.Title}
+			byTitle[group.Title] = group
+			groups = append(groups, group)
+		}
+		present := make(map[string]bool)
+		for _, entry := range group.Entries {
+			present[entry] = true
+		}
+		for _, entry := range newGroup.Entries {
+			if !present[entry] {
+				group.Entries = append(group.Entries, entry)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(heading + "\n")
+	if preamble != "" {
+		sb.WriteString("\n" + preamble + "\n")
+	}
+	for _, group := range groups {
+		if len(group.Entries) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n### %s\n\n", group.Title))
+		for _, entry := range group.Entries {
+			sb.WriteString(entry + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func sortedPackageNames(pkgs map[string]modface.PackageInterface) []string {
+	names := []string{}
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedExports(faces map[string]modface.Export) []modface.Export {
+	sorted := []modface.Export{}
+	for _, face := range faces {
+		sorted = append(sorted, face)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID() < sorted[j].ID()
+	})
+	return sorted
+}
+
+func sortedExportDifferences(diffs map[string]modface.ExportDifference) []modface.ExportDifference {
+	sorted := []modface.ExportDifference{}
+	for _, facediff := range diffs {
+		sorted = append(sorted, facediff)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].New.ID() < sorted[j].New.ID()
+	})
+	return sorted
+}
+
+// exportName returns the ID of an export without the leading separator of receiverless functions.
+func exportName(face modface.Export) string {
+	return strings.TrimPrefix(face.ID(), ".")
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testChangelog = changelogHeader + `
+## [Unreleased]
+
+Work towards the next release.
+
+### Added
+
+- Hand-written entry
+- ` + "`func Qux()` to `example.com/tm/foo`" + `
+
+### Fixed
+
+- Crash on empty input
+
+## [v1.0.0] - 2024-01-01
+
+### Added
+
+- Package ` + "`example.com/tm/foo`" + `
+`
+
+const testSection = "## [Unreleased]\n\n### Breaking changes\n\n" +
+	"- Changed `Foo` in `example.com/tm/foo`\n  - old: `func Foo(int) int`\n  - new: `func Foo(int, int) int`\n" +
+	"\n### Added\n\n- `func Qux()` to `example.com/tm/foo`\n- `func Baz()` to `example.com/tm/foo`\n"
+
+func writeTestChangelog(t *testing.T, content string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "changelog-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "CHANGELOG.md")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readTestChangelog(t *testing.T, path string) string {
+	t.Helper()
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestPrependChangelogMergesUnreleased(t *testing.T) {
+	path := writeTestChangelog(t, testChangelog)
+	if err := prependChangelog(path, testSection); err != nil {
+		t.Fatal(err)
+	}
+
+	want := changelogHeader + `
+## [Unreleased]
+
+Work towards the next release.
+
+### Added
+
+- Hand-written entry
+- ` + "`func Qux()` to `example.com/tm/foo`" + `
+- ` + "`func Baz()` to `example.com/tm/foo`" + `
+
+### Fixed
+
+- Crash on empty input
+
+### Breaking changes
+
+- Changed ` + "`Foo` in `example.com/tm/foo`" + `
+  - old: ` + "`func Foo(int) int`" + `
+  - new: ` + "`func Foo(int, int) int`" + `
+
+## [v1.0.0] - 2024-01-01
+
+### Added
+
+- Package ` + "`example.com/tm/foo`" + `
+`
+	if got := readTestChangelog(t, path); got != want {
+		t.Errorf("prependChangelog() wrote:\n%s\nwant:\n%s", got, want)
+	}
+
+	// merging again changes nothing
+	if err := prependChangelog(path, testSection); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestChangelog(t, path); got != want {
+		t.Errorf("second prependChangelog() wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrependChangelogReleasesUnreleased(t *testing.T) {
+	path := writeTestChangelog(t, testChangelog)
+	section := strings.Replace(testSection, "## [Unreleased]", "## [v1.1.0] - 2024-02-01", 1)
+	if err := prependChangelog(path, section); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readTestChangelog(t, path)
+	if strings.Contains(got, "Unreleased") {
+		t.Errorf("released changelog still has an Unreleased section:\n%s", got)
+	}
+	if !strings.Contains(got, "## [v1.1.0] - 2024-02-01\n\nWork towards the next release.\n\n### Added\n\n- Hand-written entry\n") {
+		t.Errorf("released section does not keep the Unreleased entries:\n%s", got)
+	}
+	if strings.Count(got, "## [v1.0.0]") != 1 {
+		t.Errorf("previous release is not kept once:\n%s", got)
+	}
+}
+
+func TestPrependChangelogNewFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "changelog-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "CHANGELOG.md")
+
+	if err := prependChangelog(path, testSection); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readTestChangelog(t, path), changelogHeader+"\n"+testSection; got != want {
+		t.Errorf("prependChangelog() wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
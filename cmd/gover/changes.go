@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+// change is a single difference between module interfaces, flattened for reporting.
+type change struct {
+	Kind     string
+	Package  string
+	Old      modface.Export // nil for additions
+	New      modface.Export // nil for removals
+	Breaking bool
+	Allowed  string // reason a breaking change was allowed, if any
+	Unstable bool
+}
+
+// Export returns the most recent version of the changed export, or nil for package changes.
+func (c change) Export() modface.Export {
+	if c.New != nil {
+		return c.New
+	}
+	return c.Old
+}
+
+// Position returns the position of the change in the new version, or in the old version for
+// removals.
+func (c change) Position() (file string, line int) {
+	if face := c.Export(); face != nil {
+		return face.Position().Filename, face.Position().Line
+	}
+	return "", 0
+}
+
+// Message returns a human-readable description of the change.
+func (c change) Message() string {
+	var msg string
+	switch c.Kind {
+	case "changed-module-path":
+		msg = fmt.Sprintf("module path changed to %s", c.Package)
+	case "removed-package":
+		msg = fmt.Sprintf("package %s removed", c.Package)
+	case "added-package":
+		msg = fmt.Sprintf("package %s added", c.Package)
+	case "changed-signature":
+		msg = fmt.Sprintf("%s changed from %s to %s", qualifiedName(c.Package, c.New), c.Old, c.New)
+	case "deprecated-func", "deprecated-method":
+		msg = fmt.Sprintf("%s deprecated", qualifiedName(c.Package, c.New))
+	default:
+		if c.New != nil {
+			msg = fmt.Sprintf("%s added: %s", qualifiedName(c.Package, c.New), c.New)
+		} else {
+			msg = fmt.Sprintf("%s removed: %s", qualifiedName(c.Package, c.Old), c.Old)
+		}
+	}
+
+	if c.Allowed != "" {
+		msg += fmt.Sprintf(" (allowed: %s)", c.Allowed)
+	} else if c.Unstable && (c.Old != nil || c.Kind == "removed-package") {
+		msg += " (unstable package)"
+	}
+	return msg
+}
+
+// exportKind returns the kind of declaration of an export.
+func exportKind(face modface.Export) string {
+	if fs, ok := face.(modface.FuncSignature); ok && fs.Receiver.IsDefined() {
+		return "method"
+	}
+	return "func"
+}
+
+// listChanges flattens a module difference into a list of changes sorted by package and export.
+// If level is "breaking", only breaking and allowed breaking changes are listed.
+func listChanges(md *modface.ModuleDifference, level string) []change {
+	changes := []change{}
+	add := func(c change) {
+		if level != "breaking" || c.Breaking || c.Allowed != "" {
+			changes = append(changes, c)
+		}
+	}
+
+	if !md.ModPathsMatch {
+		add(change{Kind: "changed-module-path", Package: md.ModPath, Breaking: true})
+		return changes
+	}
+
+	for pkgname := range md.PackageRemovals {
+		unstable := md.Unstable[pkgname]
+		add(change{Kind: "removed-package", Package: pkgname, Allowed: md.Allowed[pkgname],
+			Unstable: unstable, Breaking: md.Allowed[pkgname] == "" && !unstable})
+	}
+	for pkgname := range md.PackageAdditions {
+		add(change{Kind: "added-package", Package: pkgname, Unstable: md.Unstable[pkgname]})
+	}
+	for pkgname, pkgchanges := range md.PackageChanges {
+		unstable := md.Unstable[pkgname]
+		for id, face := range pkgchanges.Removals {
+			allowed := pkgchanges.Allowed[id]
+			add(change{Kind: "removed-" + exportKind(face), Package: pkgname, Old: face,
+				Allowed: allowed, Unstable: unstable, Breaking: allowed == "" && !unstable})
+		}
+		for _, face := range pkgchanges.Additions {
+			add(change{Kind: "added-" + exportKind(face), Package: pkgname, New: face,
+				Unstable: unstable})
+		}
+		for id, facediff := range pkgchanges.Changes {
+			allowed := pkgchanges.Allowed[id]
+			add(change{Kind: "changed-signature", Package: pkgname, Old: facediff.Old,
+				New: facediff.New, Allowed: allowed, Unstable: unstable,
+				Breaking: allowed == "" && !unstable})
+		}
+		for _, facediff := range pkgchanges.Deprecations {
+			add(change{Kind: "deprecated-" + exportKind(facediff.New), Package: pkgname,
+				Old: facediff.Old, New: facediff.New, Unstable: unstable})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		idi, idj := "", ""
+		if face := changes[i].Export(); face != nil {
+			idi = face.ID()
+		}
+		if face := changes[j].Export(); face != nil {
+			idj = face.ID()
+		}
+		if idi != idj {
+			return idi < idj
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+
+	return changes
+}
+
+// changeCategory returns the category of a change, such as "removed".
+func changeCategory(c change) string {
+	return strings.SplitN(c.Kind, "-", 2)[0]
+}
+
+// changeCounts are the numbers of each category of change in a package.
+type changeCounts struct {
+	Added, Removed, Changed, Deprecated int
+}
+
+// Total returns the total number of changes.
+func (cc changeCounts) Total() int {
+	return cc.Added + cc.Removed + cc.Changed + cc.Deprecated
+}
+
+// countChanges counts the categories of changes per package.
+// The package names are returned in order of first appearance in changes.
+func countChanges(changes []change) ([]string, map[string]*changeCounts) {
+	pkgnames := []string{}
+	counts := make(map[string]*changeCounts)
+	for _, c := range changes {
+		pc, found := counts[c.Package]
+		if !found {
+			pc = new(changeCounts)
+			counts[c.Package] = pc
+			pkgnames = append(pkgnames, c.Package)
+		}
+		switch changeCategory(c) {
+		case "added":
+			pc.Added++
+		case "removed":
+			pc.Removed++
+		case "changed":
+			pc.Changed++
+		case "deprecated":
+			pc.Deprecated++
+		}
+	}
+	return pkgnames, counts
+}
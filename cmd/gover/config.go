@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dgravesa/gover/pkg/allowlist"
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/minicli"
+	"gopkg.in/yaml.v3"
+)
+
+// configFiles are the names of project configuration files recognized in a module root.
+var configFiles = []string{".gover.yaml", ".gover.yml", ".gover.toml"}
+
+// config is a project configuration.
+// Any setting which is not one of the reserved keys exclude, unstable, allow, or commands sets the
+// default value of the flag with the same name for every command that has it and accepts the value.
+// Settings under commands set flag defaults for a specific command and take precedence.
+// Flags specified on the command line always take precedence over the configuration.
+type config struct {
+	File     string
+	Flags    map[string]string
+	Commands map[string]map[string]string
+	Exclude  []string
+	Unstable []string
+	Allow    allowlist.List
+
+	// set by the main command rather than the configuration file
+	jobs int             // from the -j flag
+	ctx  context.Context // canceled on interrupt or when the -timeout flag expires
+}
+
+// load reads the configuration file from the module root at modpath, if there is one.
+func (cfg *config) load(modpath string) error {
+	*cfg = config{
+		Flags:    make(map[string]string),
+		Commands: make(map[string]map[string]string),
+	}
+
+	var raw map[string]interface{}
+	for _, name := range configFiles {
+		filename := filepath.Join(modpath, name)
+		content, err := ioutil.ReadFile(filename)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		} else if cfg.File != "" {
+			return fmt.Errorf("multiple configuration files found: %s, %s", cfg.File, filename)
+		}
+
+		cfg.File = filename
+		if filepath.Ext(name) == ".toml" {
+			err = toml.Unmarshal(content, &raw)
+		} else {
+			err = yaml.Unmarshal(content, &raw)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", filename, err)
+		}
+	}
+
+	for key, value := range raw {
+		var err error
+		switch key {
+		case "exclude":
+			cfg.Exclude, err = configStrings(value)
+		case "unstable":
+			cfg.Unstable, err = configStrings(value)
+		case "allow":
+			cfg.Allow, err = configAllowlist(value, cfg.File)
+		case "commands":
+			cmds, ok := value.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("expected a table of commands")
+				break
+			}
+			for name, settings := range cmds {
+				if cfg.Commands[name], err = configFlags(settings); err != nil {
+					err = fmt.Errorf("%s: %v", name, err)
+					break
+				}
+			}
+		default:
+			cfg.Flags[key], err = configScalar(value)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s: %v", cfg.File, key, err)
+		}
+	}
+
+	return nil
+}
+
+// options returns the library options for the packages configured as excluded or unstable,
+// using the default module cache when it is available.
+func (cfg *config) options(opts ...gover.Option) []gover.Option {
+	cache, _ := gover.DefaultCache()
+	return append([]gover.Option{
+		gover.WithExclude(cfg.Exclude...),
+		gover.WithUnstable(cfg.Unstable...),
+		gover.WithCache(cache),
+		gover.WithJobs(cfg.jobs),
+	}, opts...)
+}
+
+// parseModule parses the module in dir, leaving out any excluded packages and marking any
+// configured unstable packages.
+func (cfg *config) parseModule(dir string) (*modface.Module, error) {
+	return gover.ParseModule(cfg.ctx, dir, cfg.options()...)
+}
+
+// applyFlagDefaults sets the configured defaults of a command's flags, skipping the flags in isSet.
+// A setting which is not specific to the command is skipped if the command refuses its value,
+// since the same flag name may accept different values in different commands.
+func (cfg *config) applyFlagDefaults(cmdname string, flags *flag.FlagSet, isSet map[string]bool) error {
+	cmdsettings := cfg.Commands[cmdname]
+	for name, value := range cfg.Flags {
+		if _, found := cmdsettings[name]; found || isSet[name] || flags.Lookup(name) == nil {
+			continue
+		}
+		_ = flags.Set(name, value)
+	}
+	for name, value := range cmdsettings {
+		if isSet[name] || flags.Lookup(name) == nil {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("%s: %s: %s: %v", cfg.File, cmdname, name, err)
+		}
+	}
+	return nil
+}
+
+func configScalar(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool, int, int64, float64:
+		return fmt.Sprint(v), nil
+	case time.Time:
+		return v.Format("2006-01-02"), nil
+	}
+	return "", fmt.Errorf("expected a single value")
+}
+
+func configStrings(value interface{}) ([]string, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list")
+	}
+	strs := []string{}
+	for _, item := range list {
+		str, err := configScalar(item)
+		if err != nil {
+			return nil, err
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
+
+func configFlags(value interface{}) (map[string]string, error) {
+	table, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a table of settings")
+	}
+	flags := make(map[string]string)
+	for name, item := range table {
+		str, err := configScalar(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		flags[name] = str
+	}
+	return flags, nil
+}
+
+func configAllowlist(value interface{}, filename string) (allowlist.List, error) {
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case []map[string]interface{}:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	default:
+		return nil, fmt.Errorf("expected a list of entries")
+	}
+
+	list := allowlist.List{}
+	for i, item := range items {
+		fields, err := configFlags(item)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %v", i+1, err)
+		}
+		entry, err := allowlist.NewEntry(fields["package"], fields["export"], fields["expires"],
+			fields["reason"])
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %v", i+1, err)
+		}
+		entry.Source = fmt.Sprintf("%s: allow entry %d", filename, i+1)
+		list = append(list, entry)
+	}
+	return list, nil
+}
+
+// configuredCmds wraps commands so that the project configuration is loaded and applied to
+// their flags before they are executed.
+type configuredCmds struct {
+	ctx     context.Context // injected by main command
+	modpath *string         // injected by main command
+	jobs    *int            // injected by main command
+	timeout *time.Duration  // injected by main command
+	cfg     *config
+	cmds    map[string]*configuredCmd
+}
+
+func newConfiguredCmds(ctx context.Context, modpath *string, jobs *int, timeout *time.Duration,
+	cfg *config) *configuredCmds {
+
+	return &configuredCmds{
+		ctx:     ctx,
+		modpath: modpath,
+		jobs:    jobs,
+		timeout: timeout,
+		cfg:     cfg,
+		cmds:    make(map[string]*configuredCmd),
+	}
+}
+
+// wrap returns cmd wrapped so that it is configured when executed.
+func (cc *configuredCmds) wrap(name string, cmd minicli.CmdImpl) minicli.CmdImpl {
+	wrapped := &configuredCmd{name: name, parent: cc, cmd: cmd}
+	cc.cmds[name] = wrapped
+	return wrapped
+}
+
+// validate verifies that every configured setting applies to some command, and that every setting
+// which is not specific to a command is accepted by some command with the flag.
+func (cc *configuredCmds) validate() error {
+	known := make(map[string]map[string]bool)
+	anyKnown := make(map[string][]*flag.Flag)
+	for name, cmd := range cc.cmds {
+		known[name] = make(map[string]bool)
+		cmd.flagSet().VisitAll(func(f *flag.Flag) {
+			known[name][f.Name] = true
+			anyKnown[f.Name] = append(anyKnown[f.Name], f)
+		})
+	}
+
+	for setting, value := range cc.cfg.Flags {
+		flags, found := anyKnown[setting]
+		if !found {
+			return fmt.Errorf("%s: unknown setting %s", cc.cfg.File, setting)
+		}
+		var err error
+		for _, f := range flags {
+			if err = tryFlagValue(f, value); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s: %v", cc.cfg.File, setting, err)
+		}
+	}
+	for name, settings := range cc.cfg.Commands {
+		if _, found := known[name]; !found {
+			return fmt.Errorf("%s: unknown command %s", cc.cfg.File, name)
+		}
+		for setting := range settings {
+			if !known[name][setting] {
+				return fmt.Errorf("%s: unknown setting %s for command %s", cc.cfg.File, setting, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// tryFlagValue returns the error of setting a flag to value, leaving the flag unchanged.
+// The flag value is set directly so that the flag is not marked as set on the command line.
+func tryFlagValue(f *flag.Flag, value string) error {
+	current := f.Value.String()
+	if err := f.Value.Set(value); err != nil {
+		return err
+	}
+	return f.Value.Set(current)
+}
+
+type configuredCmd struct {
+	name   string
+	parent *configuredCmds
+	cmd    minicli.CmdImpl
+	flags  *flag.FlagSet
+}
+
+func (c *configuredCmd) SetFlags(flags *flag.FlagSet) {
+	c.flags = flags
+	c.cmd.SetFlags(flags)
+}
+
+// flagSet returns the flags of the command, defining them if they have not been already.
+func (c *configuredCmd) flagSet() *flag.FlagSet {
+	if c.flags == nil {
+		c.SetFlags(flag.NewFlagSet(c.name, flag.ContinueOnError))
+	}
+	return c.flags
+}
+
+func (c *configuredCmd) Exec(args []string) error {
+	cfg := c.parent.cfg
+	if err := cfg.load(*c.parent.modpath); err != nil {
+		return err
+	} else if err := c.parent.validate(); err != nil {
+		return err
+	}
+	cfg.jobs = *c.parent.jobs
+	cfg.ctx = c.parent.ctx
+	if *c.parent.timeout > 0 {
+		var cancel context.CancelFunc
+		cfg.ctx, cancel = context.WithTimeout(cfg.ctx, *c.parent.timeout)
+		defer cancel()
+	}
+
+	// apply configured defaults to flags not set on the command line
+	flags := c.flagSet()
+	isSet := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		isSet[f.Name] = true
+	})
+	if err := cfg.applyFlagDefaults(c.name, flags, isSet); err != nil {
+		return err
+	}
+
+	return c.cmd.Exec(args)
+}
+
+// configEntry is the printable representation of an allowlist entry.
+type configEntry struct {
+	Package string `yaml:"package"`
+	Export  string `yaml:"export"`
+	Expires string `yaml:"expires"`
+	Reason  string `yaml:"reason"`
+}
+
+// effectiveConfig is the printable representation of a merged configuration.
+type effectiveConfig struct {
+	File     string                       `yaml:"file,omitempty"`
+	Exclude  []string                     `yaml:"exclude,omitempty"`
+	Unstable []string                     `yaml:"unstable,omitempty"`
+	Allow    []configEntry                `yaml:"allow,omitempty"`
+	Commands map[string]map[string]string `yaml:"commands"`
+}
+
+type configCmd struct {
+	cmds *configuredCmds
+}
+
+func newConfigCmd(cmds *configuredCmds) minicli.CmdImpl {
+	return &configCmd{cmds: cmds}
+}
+
+func (cc *configCmd) SetFlags(flags *flag.FlagSet) {}
+
+func (cc *configCmd) Exec(args []string) error {
+	cfg := cc.cmds.cfg
+	if err := cfg.load(*cc.cmds.modpath); err != nil {
+		return err
+	} else if err := cc.cmds.validate(); err != nil {
+		return err
+	}
+
+	effective := effectiveConfig{
+		File:     cfg.File,
+		Exclude:  cfg.Exclude,
+		Unstable: cfg.Unstable,
+		Commands: make(map[string]map[string]string),
+	}
+	for _, entry := range cfg.Allow {
+		effective.Allow = append(effective.Allow, configEntry{
+			Package: entry.Package,
+			Export:  entry.Export,
+			Expires: entry.Expires.Format("2006-01-02"),
+			Reason:  entry.Reason,
+		})
+	}
+
+	names := []string{}
+	for name := range cc.cmds.cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flags := cc.cmds.cmds[name].flagSet()
+		if err := cfg.applyFlagDefaults(name, flags, nil); err != nil {
+			return err
+		}
+		settings := make(map[string]string)
+		flags.VisitAll(func(f *flag.Flag) {
+			settings[f.Name] = f.Value.String()
+		})
+		if len(settings) > 0 {
+			effective.Commands[name] = settings
+		}
+	}
+
+	out, err := yaml.Marshal(effective)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestApplyFlagDefaults(t *testing.T) {
+	newFlags := func() (*flag.FlagSet, *optset, *int) {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		format := makeOptsetFlag(flags, "format", "output format", "text", "json")
+		limit := flags.Int("limit", 10, "limit")
+		return flags, format, limit
+	}
+
+	tests := []struct {
+		name       string
+		cfg        config
+		isSet      map[string]bool
+		wantFormat string
+		wantLimit  int
+		wantErr    string
+	}{
+		{
+			name:       "global",
+			cfg:        config{Flags: map[string]string{"format": "json", "limit": "5"}},
+			wantFormat: "json",
+			wantLimit:  5,
+		},
+		{
+			name:       "global value refused by command",
+			cfg:        config{Flags: map[string]string{"format": "sarif", "limit": "5"}},
+			wantFormat: "text",
+			wantLimit:  5,
+		},
+		{
+			name: "command overrides global",
+			cfg: config{
+				Flags:    map[string]string{"format": "sarif"},
+				Commands: map[string]map[string]string{"test": {"format": "json"}},
+			},
+			wantFormat: "json",
+			wantLimit:  10,
+		},
+		{
+			name: "command value refused",
+			cfg: config{
+				File:     ".gover.yaml",
+				Commands: map[string]map[string]string{"test": {"format": "sarif"}},
+			},
+			wantErr: ".gover.yaml: test: format: invalid selection for format: sarif",
+		},
+		{
+			name:       "set on command line",
+			cfg:        config{Commands: map[string]map[string]string{"test": {"format": "sarif"}}},
+			isSet:      map[string]bool{"format": true},
+			wantFormat: "text",
+			wantLimit:  10,
+		},
+		{
+			name:       "setting for other command",
+			cfg:        config{Commands: map[string]map[string]string{"other": {"format": "json"}}},
+			wantFormat: "text",
+			wantLimit:  10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, format, limit := newFlags()
+			err := tt.cfg.applyFlagDefaults("test", flags, tt.isSet)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyFlagDefaults() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, _ := format.Value(); got != tt.wantFormat {
+				t.Errorf("format = %q, want %q", got, tt.wantFormat)
+			}
+			if *limit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", *limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+// flagsCmd is a command which only defines flags.
+type flagsCmd struct {
+	setFlags func(flags *flag.FlagSet)
+}
+
+func (fc flagsCmd) SetFlags(flags *flag.FlagSet) { fc.setFlags(flags) }
+
+func (fc flagsCmd) Exec(args []string) error { return nil }
+
+func TestValidate(t *testing.T) {
+	newCmds := func(cfg config) *configuredCmds {
+		cfg.File = ".gover.yaml"
+		cmds := newConfiguredCmds(context.Background(), nil, nil, nil, &cfg)
+		cmds.wrap("text", flagsCmd{func(flags *flag.FlagSet) {
+			makeOptsetFlag(flags, "format", "output format", "text", "json")
+			flags.Int("limit", 10, "limit")
+		}})
+		cmds.wrap("sarif", flagsCmd{func(flags *flag.FlagSet) {
+			makeOptsetFlag(flags, "format", "output format", "text", "sarif")
+		}})
+		return cmds
+	}
+
+	tests := []struct {
+		name    string
+		cfg     config
+		wantErr string
+	}{
+		{
+			name: "global value accepted by every command",
+			cfg:  config{Flags: map[string]string{"format": "text", "limit": "5"}},
+		},
+		{
+			name: "global value refused by some commands",
+			cfg:  config{Flags: map[string]string{"format": "sarif"}},
+		},
+		{
+			name:    "global value refused by every command",
+			cfg:     config{Flags: map[string]string{"format": "sarf"}},
+			wantErr: ".gover.yaml: format: invalid selection for format: sarf",
+		},
+		{
+			name:    "global value refused by only command",
+			cfg:     config{Flags: map[string]string{"limit": "abc"}},
+			wantErr: ".gover.yaml: limit:",
+		},
+		{
+			name:    "unknown setting",
+			cfg:     config{Flags: map[string]string{"colour": "never"}},
+			wantErr: ".gover.yaml: unknown setting colour",
+		},
+		{
+			name:    "unknown command setting",
+			cfg:     config{Commands: map[string]map[string]string{"sarif": {"limit": "5"}}},
+			wantErr: ".gover.yaml: unknown setting limit for command sarif",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds := newCmds(tt.cfg)
+			err := cmds.validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("validate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			// validation leaves the flags unchanged and unset
+			for name, cmd := range cmds.cmds {
+				cmd.flagSet().Visit(func(f *flag.Flag) {
+					t.Errorf("%s: flag %s set by validate()", name, f.Name)
+				})
+				if f := cmd.flagSet().Lookup("format"); f.Value.String() != "text" {
+					t.Errorf("%s: format = %q after validate(), want %q", name, f.Value, "text")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/gover/pkg/usage"
+	"golang.org/x/mod/module"
+)
+
+// consumer is a module which may use the exports of the module being versioned.
+type consumer struct {
+	Name string // consumer as specified on the command line
+	Dir  string
+}
+
+// parseConsumers resolves a comma-separated list of consumers, each of which is either a local
+// module directory or a module@version in the module cache.
+func parseConsumers(ctx context.Context, list string) ([]consumer, error) {
+	consumers := []consumer{}
+	var modcache string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		i := strings.LastIndex(name, "@")
+		if i < 0 {
+			consumers = append(consumers, consumer{Name: name, Dir: name})
+			continue
+		}
+
+		// module cache directories are named by escaped module path and version
+		if modcache == "" {
+			var err error
+			if modcache, err = moduleCacheDir(ctx); err != nil {
+				return nil, err
+			}
+		}
+		escpath, err := module.EscapePath(name[:i])
+		if err != nil {
+			return nil, err
+		}
+		escversion, err := module.EscapeVersion(name[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		dir := filepath.Join(modcache, escpath+"@"+escversion)
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("%s is not in the module cache, run go mod download %s", name, name)
+		}
+		consumers = append(consumers, consumer{Name: name, Dir: dir})
+	}
+
+	if len(consumers) == 0 {
+		return nil, fmt.Errorf("no consumers specified")
+	}
+	return consumers, nil
+}
+
+// moduleCacheDir returns the module cache directory, which is GOMODCACHE, or the pkg/mod
+// directory of the first GOPATH entry for go versions before 1.15 which do not report GOMODCACHE.
+func moduleCacheDir(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "go", "env", "GOMODCACHE", "GOPATH").Output()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	} else if err != nil {
+		return "", fmt.Errorf("go env GOMODCACHE GOPATH: %v", err)
+	}
+	return parseModuleCacheDir(string(out))
+}
+
+// parseModuleCacheDir returns the module cache directory from the output of
+// go env GOMODCACHE GOPATH, which is a line for each variable.
+func parseModuleCacheDir(goenv string) (string, error) {
+	lines := strings.Split(goenv, "\n")
+	if modcache := strings.TrimSpace(lines[0]); modcache != "" {
+		return modcache, nil
+	}
+	if len(lines) > 1 {
+		for _, gopath := range filepath.SplitList(strings.TrimSpace(lines[1])) {
+			if gopath != "" {
+				return filepath.Join(gopath, "pkg", "mod"), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("cannot locate the module cache, neither GOMODCACHE nor GOPATH is set")
+}
+
+// scanConsumers returns the references of each consumer to exports of target.
+func scanConsumers(ctx context.Context, consumers []consumer,
+	target *modface.Module) (map[string][]usage.Reference, error) {
+
+	refs := make(map[string][]usage.Reference)
+	for _, c := range consumers {
+		crefs, err := usage.Scan(ctx, c.Dir, target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Name, err)
+		}
+		for i := range crefs {
+			crefs[i].Pos.Filename = filepath.ToSlash(filepath.Join(c.Dir, crefs[i].Pos.Filename))
+		}
+		refs[c.Name] = crefs
+	}
+	return refs, nil
+}
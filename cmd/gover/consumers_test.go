@@ -0,0 +1,32 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseModuleCacheDir(t *testing.T) {
+	gopaths := strings.Join([]string{filepath.FromSlash("/home/go"), filepath.FromSlash("/other/go")},
+		string(filepath.ListSeparator))
+
+	tests := []struct {
+		goenv   string
+		want    string
+		wantErr bool
+	}{
+		{"/cache/mod\n/home/go\n", "/cache/mod", false},
+		{"\n/home/go\n", filepath.Join("/home/go", "pkg", "mod"), false},
+		{"\n" + gopaths + "\n", filepath.Join(filepath.FromSlash("/home/go"), "pkg", "mod"), false},
+		{"\n\n", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseModuleCacheDir(tt.goenv)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseModuleCacheDir(%q) error = %v, want error %v", tt.goenv, err, tt.wantErr)
+		} else if got != tt.want {
+			t.Errorf("parseModuleCacheDir(%q) = %q, want %q", tt.goenv, got, tt.want)
+		}
+	}
+}
@@ -3,30 +3,48 @@ package main
 import (
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"os/exec"
+	"time"
 
+	"github.com/dgravesa/gover/pkg/allowlist"
+	"github.com/dgravesa/gover/pkg/gover"
 	"github.com/dgravesa/gover/pkg/modface"
 	"github.com/dgravesa/minicli"
 )
 
 type diffCmd struct {
 	modpath  *string // injected by main command
+	cfg      *config // injected by main command
 	pchanges *optset
 	errcond  *optset
+	format   *optset
 	compare  string
+	allow    string
+	reqdep   bool
+	limit    int
+	color    *optset
+	stat     bool
+	policy   *policyFlags
 }
 
-func newDiffCmd(modpath *string) minicli.CmdImpl {
-	return &diffCmd{modpath: modpath}
+func newDiffCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &diffCmd{modpath: modpath, cfg: cfg}
 }
 
 func (d *diffCmd) SetFlags(flags *flag.FlagSet) {
 	d.pchanges = makeOptsetFlag(flags, "changes", "changes to print", "any", "breaking")
 	d.errcond = makeOptsetFlag(flags, "error", "condition to exit with error status code",
 		"none", "breaking", "any")
+	d.format = makeOptsetFlag(flags, "format", "output format", "text", "github", "sarif", "junit", "markdown")
+	flags.IntVar(&d.limit, "limit", 50, "maximum number of changes to detail in markdown format")
+	d.color = makeOptsetFlag(flags, "color", "colorize text output", "auto", "always", "never")
+	flags.BoolVar(&d.stat, "stat", false, "print a summary of changes per package instead of the changes")
+	d.policy = makePolicyFlags(flags)
 	flags.StringVar(&d.compare, "compare", "HEAD", "specify commit or tag to compare against")
+	flags.StringVar(&d.allow, "allow", "",
+		"file listing intentional breaking changes (default .gover-allow in module)")
+	flags.BoolVar(&d.reqdep, "require-deprecation", false,
+		"exit with error status code if exports are removed without being deprecated in a prior version")
 }
 
 func (d *diffCmd) Exec(args []string) error {
@@ -39,182 +57,146 @@ func (d *diffCmd) Exec(args []string) error {
 	if err != nil {
 		return err
 	}
+	format, err := d.format.Value()
+	if err != nil {
+		return err
+	}
+	color, err := d.color.Value()
+	if err != nil {
+		return err
+	}
 
-	moduleDifference, err := diff(*d.modpath, d.compare)
+	compareModule, currentModule, err := gover.ParseCompared(d.cfg.ctx, *d.modpath, d.compare,
+		d.cfg.options()...)
 	if err != nil {
 		return err
 	}
+	moduleDifference := modface.Diff(compareModule, currentModule)
 
-	// print differences to stdout as specified by change level
-	printDiff(moduleDifference, pchanges)
+	// allow intentional breaking changes
+	var allowed allowlist.List
+	if d.allow != "" {
+		allowed, err = allowlist.Load(d.allow)
+	} else {
+		allowed, err = allowlist.LoadDefault(*d.modpath)
+	}
+	if err != nil {
+		return err
+	}
+	allowed = append(allowed, d.cfg.Allow...)
+	for _, warning := range allowed.Apply(moduleDifference, time.Now()) {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
 
-	var resultStatus error
-	switch errcond {
-	case "breaking":
-		if moduleDifference.Breaking() {
-			resultStatus = fmt.Errorf("breaking changes detected")
+	// print differences to stdout as specified by change level and format
+	switch format {
+	case "github", "sarif", "junit":
+		git, err := gitRepo(*d.modpath)
+		if err != nil {
+			return err
 		}
-	case "any":
-		if moduleDifference.Any() {
-			resultStatus = fmt.Errorf("changes detected")
+		repodir := git.Prefix
+		switch format {
+		case "github":
+			printGithub(moduleDifference, pchanges, repodir)
+		case "sarif":
+			err = writeSarif(os.Stdout, moduleDifference, pchanges, repodir)
+		case "junit":
+			err = writeJunit(os.Stdout, moduleDifference, sortedPackageNames(currentModule.Packages),
+				pchanges, repodir)
+		}
+		if err != nil {
+			return err
 		}
+	case "markdown":
+		suggestion, err := d.suggestion()
+		if err != nil {
+			return err
+		}
+		fmt.Print(writeMarkdown(moduleDifference, pchanges, suggestion, d.limit))
 	default:
-		resultStatus = nil
+		if d.stat {
+			err = printStat(moduleDifference, pchanges, newPalette(color))
+		} else {
+			printDiff(moduleDifference, pchanges, newPalette(color))
+		}
+		if err != nil {
+			return err
+		}
 	}
 
-	return resultStatus
-}
-
-func diff(modpath string, compareID string) (*modface.ModuleDifference, error) {
-	var currentModule *modface.Module
-	var compareModule *modface.Module
-	currentDone := make(chan error)
-	recentDone := make(chan error)
-
-	// parse current module interface
-	go func() {
-		var err error
-		currentModule, err = modface.ParseModule(modpath)
-		currentDone <- err
-	}()
-
-	// copy module and checkout compare version
-	go func() {
-		// create temporary directory
-		tmpdir, err := ioutil.TempDir(os.TempDir(), "gover-*")
+	var resultStatus error
+	if d.reqdep {
+		undeprecated, err := removedWithoutDeprecation(*d.modpath, moduleDifference, d.cfg)
 		if err != nil {
-			recentDone <- err
-			return
+			return err
 		}
-
-		// copy module into temporary directory
-		cpycmd := exec.Command("cp", "-r", fmt.Sprintf("%s/", modpath), tmpdir)
-		_, cperr := cpycmd.Output()
-
-		// NOTE: doing this manually because defer does not seem to like it
-		rmdir := func() {
-			rmcmd := exec.Command("rm", "-rf", tmpdir)
-			_, rmerr := rmcmd.Output()
-			// verify rm succeeded
-			switch v := rmerr.(type) {
-			case nil:
-				break
-			case *exec.ExitError:
-				fmt.Fprintln(os.Stderr, rmcmd.String(), string(v.Stderr))
-			default:
-				fmt.Fprintln(os.Stderr, "error:", v)
+		for _, pkgname := range sortedPackageNames(undeprecated) {
+			for _, face := range sortedExports(undeprecated[pkgname]) {
+				fmt.Fprintf(os.Stderr, "violation: %s removed without prior deprecation\n",
+					qualifiedName(pkgname, face))
+				resultStatus = fmt.Errorf("exports removed without deprecation")
 			}
 		}
+	}
 
-		// verify copy succeeded
-		switch v := cperr.(type) {
-		case nil:
-			break
-		case *exec.ExitError:
-			rmdir()
-			recentDone <- fmt.Errorf("%s: %s", cpycmd, string(v.Stderr))
-			return
-		default:
-			rmdir()
-			recentDone <- fmt.Errorf("%s: %v", cpycmd, cperr)
-			return
+	switch errcond {
+	case "breaking":
+		if moduleDifference.Breaking() {
+			resultStatus = fmt.Errorf("breaking changes detected")
 		}
-
-		// checkout recent version
-		checkoutcmd := exec.Command("git", "-C", tmpdir, "checkout", "-f", compareID)
-		_, checkouterr := checkoutcmd.Output()
-		switch v := checkouterr.(type) {
-		case nil:
-			break
-		case *exec.ExitError:
-			rmdir()
-			recentDone <- fmt.Errorf("%s: %s", checkoutcmd, string(v.Stderr))
-			return
-		default:
-			rmdir()
-			recentDone <- fmt.Errorf("%s: %v", checkoutcmd, err)
-			return
+	case "any":
+		if moduleDifference.Any() {
+			resultStatus = fmt.Errorf("changes detected")
 		}
-
-		// parse version of module
-		compareModule, err = modface.ParseModule(tmpdir)
-		rmdir()
-		recentDone <- err
-	}()
-
-	// wait for results
-	currentErr := <-currentDone
-	recentErr := <-recentDone
-
-	if currentErr != nil {
-		return nil, currentErr
-	} else if recentErr != nil {
-		return nil, recentErr
 	}
 
-	// compute difference between module interfaces
-	moduleDifference := modface.Diff(compareModule, currentModule)
-
-	return moduleDifference, nil
+	return resultStatus
 }
 
-func printDiff(moduleDifference *modface.ModuleDifference, level string) {
-	type difference interface {
-		Any() bool
-		Breaking() bool
+// suggestion returns the suggested next version from the latest version of the module, or the
+// reason that the versioning policy could not suggest one.
+func (d *diffCmd) suggestion() (string, error) {
+	policy, err := d.policy.Policy()
+	if err != nil {
+		return "", err
 	}
 
-	meetsLevel := func(d difference) bool {
-		if level == "any" && d.Any() {
-			return true
-		} else if level == "breaking" && d.Breaking() {
-			return true
-		}
-		return false
+	// the suggestion is relative to the latest version, which may differ from the compared revision
+	next, err := gover.Suggest(d.cfg.ctx, *d.modpath, d.cfg.options(gover.WithPolicy(policy))...)
+	if d.cfg.ctx.Err() != nil {
+		return "", d.cfg.ctx.Err()
+	} else if err != nil {
+		return fmt.Sprintf("none (%v)", err), nil
 	}
+	return fmt.Sprintf("`%s`", next), nil
+}
 
-	if !meetsLevel(moduleDifference) {
-		return
-	}
+// removedWithoutDeprecation returns the exports removed in a module difference which were not
+// deprecated in any released version of the module.
+func removedWithoutDeprecation(modpath string, md *modface.ModuleDifference,
+	cfg *config) (map[string]modface.PackageInterface, error) {
 
-	// print module name
-	if !moduleDifference.ModPathsMatch {
-		fmt.Println("< module", moduleDifference.OldModPath)
-		fmt.Println("> module", moduleDifference.ModPath)
-		// do not attempt to print further since everything would be a difference
-		return
-	}
-	fmt.Println("module", moduleDifference.ModPath)
-	// print removals
-	for pkgname := range moduleDifference.PackageRemovals {
-		fmt.Println("< package", pkgname)
-	}
-	// print additions
-	for pkgname := range moduleDifference.PackageAdditions {
-		fmt.Println("> package", pkgname)
+	versions, err := gover.Versions(cfg.ctx, modpath)
+	if err != nil {
+		return nil, err
 	}
-	// print changes per package
-	for pkgname, pkgchanges := range moduleDifference.PackageChanges {
-		if !meetsLevel(pkgchanges) {
-			continue
-		}
 
-		fmt.Println("---", "package", pkgname)
+	previous := []*modface.Module{}
+	err = gover.ParseRevisions(cfg.ctx, modpath, versions, func(_ string, module *modface.Module) error {
+		previous = append(previous, module)
+		return nil
+	}, cfg.options()...)
+	if err != nil {
+		return nil, err
+	}
 
-		// print package removals
-		for _, face := range pkgchanges.Removals {
-			fmt.Println("<  ", face)
-		}
-		if level == "any" {
-			// print package additions
-			for _, face := range pkgchanges.Additions {
-				fmt.Println(">  ", face)
-			}
-		}
-		// print package changes
-		for _, facediff := range pkgchanges.Changes {
-			fmt.Println("<  ", facediff.Old)
-			fmt.Println(">  ", facediff.New)
+	undeprecated := make(map[string]modface.PackageInterface)
+	for pkgname, faces := range modface.RemovedWithoutDeprecation(md, previous) {
+		undeprecated[pkgname] = make(modface.PackageInterface)
+		for _, face := range faces {
+			undeprecated[pkgname][face.ID()] = face
 		}
 	}
+	return undeprecated, nil
 }
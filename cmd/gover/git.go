@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dgravesa/gover/pkg/vcs"
+	"golang.org/x/mod/semver"
+)
+
+// gitRepo returns the git repository of the module at modpath.
+func gitRepo(modpath string) (*vcs.Git, error) {
+	v, err := vcs.Detect(modpath)
+	if err != nil {
+		return nil, err
+	}
+	git, ok := v.(*vcs.Git)
+	if !ok {
+		return nil, fmt.Errorf("%s is not in a git repository", modpath)
+	}
+	return git, nil
+}
+
+// gitOutput runs a git command against the repository of the module at modpath and returns its
+// trimmed output.
+func gitOutput(ctx context.Context, modpath string, args ...string) (string, error) {
+	git, err := gitRepo(modpath)
+	if err != nil {
+		return "", err
+	}
+	return git.Output(ctx, args...)
+}
+
+// tagPrefix returns the prefix of the version tags of the module at modpath, which is the module
+// directory for nested modules.
+func tagPrefix(modpath string) string {
+	if v, err := vcs.Detect(modpath); err == nil {
+		return v.TagPrefix()
+	}
+	return ""
+}
+
+// versionTag returns the tag of a revision of the module at modpath which is a version.
+// Revisions which are not versions are returned unchanged.
+func versionTag(modpath, rev string) string {
+	if semver.IsValid(rev) {
+		return tagPrefix(modpath) + rev
+	}
+	return rev
+}
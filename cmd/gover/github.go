@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+// printGithub prints module differences as GitHub Actions workflow commands, which annotate the
+// changed declarations in pull requests. Breaking changes are reported as errors, allowed breaking
+// changes and deprecations as warnings, and other changes as notices.
+// The repodir is the path of the module root relative to the repository root.
+func printGithub(md *modface.ModuleDifference, level, repodir string) {
+	for _, c := range listChanges(md, level) {
+		command := "notice"
+		title := "API change"
+		if c.Breaking {
+			command = "error"
+			title = "Breaking API change"
+		} else if c.Allowed != "" || strings.HasPrefix(c.Kind, "deprecated-") {
+			command = "warning"
+		}
+
+		params := []string{}
+		if file, line := c.Position(); file != "" {
+			params = append(params, "file="+escapeGithubProperty(path.Join(repodir, file)),
+				fmt.Sprintf("line=%d", line))
+		}
+		params = append(params, "title="+escapeGithubProperty(title+": "+c.Kind))
+
+		fmt.Printf("::%s %s::%s\n", command, strings.Join(params, ","),
+			escapeGithubData(c.Message()))
+	}
+}
+
+func escapeGithubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+func escapeGithubProperty(s string) string {
+	s = escapeGithubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/gover/pkg/versioning"
+	"github.com/dgravesa/minicli"
+	"golang.org/x/mod/semver"
+)
+
+type historyCmd struct {
+	modpath *string // injected by main command
+	cfg     *config // injected by main command
+	policy  *policyFlags
+}
+
+func newHistoryCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &historyCmd{modpath: modpath, cfg: cfg}
+}
+
+func (hc *historyCmd) SetFlags(flags *flag.FlagSet) {
+	hc.policy = makePolicyFlags(flags)
+}
+
+// release is the interface difference introduced by a version.
+type release struct {
+	Version    string
+	Previous   string
+	ModPath    string
+	Difference *modface.ModuleDifference
+	Err        error // set if the module could not be parsed at the version
+}
+
+func (hc *historyCmd) Exec(args []string) error {
+	policy, err := hc.policy.Policy()
+	if err != nil {
+		return err
+	}
+
+	releases, err := listReleases(*hc.modpath, hc.cfg)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tPREVIOUS\tTAGGED\tREQUIRED\tSTATUS\tNOTE")
+	for _, r := range releases {
+		if r.Err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\terror\t%v\n", r.Version, r.Err)
+			continue
+		} else if r.Previous == "" {
+			fmt.Fprintf(w, "%s\t-\t-\t-\tinitial\t\n", r.Version)
+			continue
+		}
+
+		change := versioning.Classify(r.Difference)
+		status, note := auditRelease(r, change, policy)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Version, r.Previous,
+			bumpLevel(r.Previous, r.Version), change, status, note)
+	}
+
+	return w.Flush()
+}
+
+// listReleases parses the module at every version and returns the difference introduced by each,
+// in version order. A version at which the module cannot be parsed is returned with its error and
+// skipped when computing the difference introduced by the next version.
+func listReleases(modpath string, cfg *config) ([]release, error) {
+	versions, err := gover.Versions(cfg.ctx, modpath)
+	if err != nil {
+		return nil, err
+	}
+
+	rp, err := gover.NewRevisionParser(cfg.ctx, modpath, cfg.options()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rp.Close()
+
+	releases := []release{}
+	var previous *modface.Module
+	var previousVersion string
+	for _, version := range versions {
+		module, err := rp.Parse(cfg.ctx, version)
+		if cfg.ctx.Err() != nil {
+			return nil, cfg.ctx.Err()
+		} else if err != nil {
+			releases = append(releases, release{Version: version, Err: err})
+			continue
+		}
+
+		r := release{Version: version, ModPath: module.Path}
+		if previous != nil {
+			r.Previous = previousVersion
+			r.Difference = modface.Diff(previous, module)
+		}
+		releases = append(releases, r)
+		previous, previousVersion = module, version
+	}
+
+	return releases, nil
+}
+
+// auditRelease reports whether a release's version is justified by its interface difference
+// under a versioning policy.
+func auditRelease(r release, change versioning.Change, policy versioning.Policy) (status, note string) {
+	if semver.Prerelease(r.Previous) != "" || semver.Prerelease(r.Version) != "" {
+		return "skipped", "pre-release"
+	}
+
+	required, err := policy.Next(r.Previous, change)
+	if err != nil {
+		return "error", err.Error()
+	} else if semver.Compare(r.Version, required) < 0 {
+		return "unjustified", fmt.Sprintf("%s → %s was tagged as a %s but %s",
+			r.Previous, r.Version, bumpLevel(r.Previous, r.Version), describeDifference(r.Difference))
+	}
+	return "ok", ""
+}
+
+// bumpLevel returns which part of a version was incremented from previous to version.
+func bumpLevel(previous, version string) string {
+	if semver.Major(previous) != semver.Major(version) {
+		return "major"
+	} else if semver.MajorMinor(previous) != semver.MajorMinor(version) {
+		return "minor"
+	} else if semver.Compare(previous, version) != 0 {
+		return "patch"
+	}
+	return "none"
+}
+
+// describeDifference returns a short description of the most significant change in a difference.
+func describeDifference(md *modface.ModuleDifference) string {
+	if !md.ModPathsMatch {
+		return fmt.Sprintf("changed module path to %s", md.ModPath)
+	}
+
+	var breaking, added, deprecated []string
+	for _, pkgname := range sortedPackageNames(md.PackageRemovals) {
+		breaking = append(breaking, fmt.Sprintf("removed package %s", pkgname))
+	}
+	pkgnames := []string{}
+	for pkgname := range md.PackageChanges {
+		pkgnames = append(pkgnames, pkgname)
+	}
+	sort.Strings(pkgnames)
+	for _, pkgname := range pkgnames {
+		pkgchanges := md.PackageChanges[pkgname]
+		for _, face := range sortedExports(pkgchanges.Removals) {
+			breaking = append(breaking, fmt.Sprintf("removed %s", qualifiedName(pkgname, face)))
+		}
+		for _, facediff := range sortedExportDifferences(pkgchanges.Changes) {
+			breaking = append(breaking, fmt.Sprintf("changed %s", qualifiedName(pkgname, facediff.New)))
+		}
+		for _, face := range sortedExports(pkgchanges.Additions) {
+			added = append(added, fmt.Sprintf("added %s", qualifiedName(pkgname, face)))
+		}
+		for _, facediff := range sortedExportDifferences(pkgchanges.Deprecations) {
+			deprecated = append(deprecated,
+				fmt.Sprintf("deprecated %s", qualifiedName(pkgname, facediff.New)))
+		}
+	}
+	for _, pkgname := range sortedPackageNames(md.PackageAdditions) {
+		added = append(added, fmt.Sprintf("added package %s", pkgname))
+	}
+
+	changes := append(append(breaking, added...), deprecated...)
+	if len(changes) == 0 {
+		return "has no interface changes"
+	} else if len(changes) == 1 {
+		return changes[0]
+	}
+	return fmt.Sprintf("%s (and %d more)", changes[0], len(changes)-1)
+}
+
+// qualifiedName returns the name of an export qualified by its package path.
+func qualifiedName(pkgname string, face modface.Export) string {
+	return fmt.Sprintf("%s.%s", pkgname, exportName(face))
+}
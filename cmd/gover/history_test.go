@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+func TestDescribeDifference(t *testing.T) {
+	foo := modface.FuncSignature{Name: "Foo"}
+	fooDeprecated := modface.FuncSignature{Name: "Foo", Deprecation: "Deprecated: use Bar."}
+	bar := modface.FuncSignature{Name: "Bar"}
+
+	module := func(face modface.PackageInterface) *modface.Module {
+		return &modface.Module{Path: "example.com/m", Packages: map[string]modface.PackageInterface{
+			"example.com/m": face,
+		}, Stability: map[string]modface.Stability{}}
+	}
+
+	tests := []struct {
+		name     string
+		old, new *modface.Module
+		want     string
+	}{
+		{"none", module(modface.PackageInterface{foo.ID(): foo}),
+			module(modface.PackageInterface{foo.ID(): foo}), "has no interface changes"},
+		{"deprecation", module(modface.PackageInterface{foo.ID(): foo}),
+			module(modface.PackageInterface{fooDeprecated.ID(): fooDeprecated}),
+			"deprecated example.com/m.Foo"},
+		{"addition before deprecation",
+			module(modface.PackageInterface{foo.ID(): foo}),
+			module(modface.PackageInterface{fooDeprecated.ID(): fooDeprecated, bar.ID(): bar}),
+			"added example.com/m.Bar (and 1 more)"},
+		{"removal first", module(modface.PackageInterface{foo.ID(): foo, bar.ID(): bar}),
+			module(modface.PackageInterface{fooDeprecated.ID(): fooDeprecated}),
+			"removed example.com/m.Bar (and 1 more)"},
+	}
+
+	for _, tt := range tests {
+		if got := describeDifference(modface.Diff(tt.old, tt.new)); got != tt.want {
+			t.Errorf("%s: describeDifference() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/gover/pkg/usage"
+	"github.com/dgravesa/minicli"
+)
+
+type impactCmd struct {
+	modpath   *string // injected by main command
+	cfg       *config // injected by main command
+	compare   string
+	consumers string
+}
+
+func newImpactCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &impactCmd{modpath: modpath, cfg: cfg}
+}
+
+func (ic *impactCmd) SetFlags(flags *flag.FlagSet) {
+	flags.StringVar(&ic.compare, "compare", "", "commit or tag that consumers use (default latest version)")
+	flags.StringVar(&ic.consumers, "consumers", "",
+		"comma-separated consumer module directories or module@version in the module cache")
+}
+
+func (ic *impactCmd) Exec(args []string) error {
+	modpath := *ic.modpath
+	ctx := ic.cfg.ctx
+
+	consumers, err := parseConsumers(ctx, ic.consumers)
+	if err != nil {
+		return err
+	}
+
+	compare := ic.compare
+	if compare == "" {
+		versions, err := gover.Versions(ctx, modpath)
+		if err != nil {
+			return err
+		} else if len(versions) == 0 {
+			return fmt.Errorf("no versions found, specify a commit with -compare")
+		}
+		compare = versions[len(versions)-1]
+	}
+
+	// consumers refer to the exports of the version they use
+	compareModule, err := gover.ParseRevision(ctx, modpath, compare, ic.cfg.options()...)
+	if err != nil {
+		return err
+	}
+	currentModule, err := ic.cfg.parseModule(modpath)
+	if err != nil {
+		return err
+	}
+	moduleDifference := modface.Diff(compareModule, currentModule)
+
+	refs, err := scanConsumers(ic.cfg.ctx, consumers, compareModule)
+	if err != nil {
+		return err
+	}
+
+	affected := 0
+	for _, c := range consumers {
+		uses := impactedUses(moduleDifference, refs[c.Name])
+		if len(uses) == 0 {
+			fmt.Printf("%s: no uses of removed or changed exports\n", c.Name)
+			continue
+		}
+		affected++
+
+		fmt.Printf("%s:\n", c.Name)
+		for _, use := range uses {
+			fmt.Printf("  %s\n", use.change.Message())
+			for _, ref := range use.refs {
+				note := ""
+				if ref.Inferred {
+					note = " (method matched by name)"
+				}
+				fmt.Printf("    %s:%d:%d%s\n", ref.Pos.Filename, ref.Pos.Line, ref.Pos.Column, note)
+			}
+		}
+	}
+
+	if affected > 0 {
+		return fmt.Errorf("%d of %d consumers use removed or changed exports since %s",
+			affected, len(consumers), compare)
+	}
+	return nil
+}
+
+// impactedUse is a removed or changed export and the references of a consumer to it.
+type impactedUse struct {
+	change change
+	refs   []usage.Reference
+}
+
+// impactedUses returns the removals and changes of a module difference that refs refer to,
+// in the order of listChanges.
+func impactedUses(md *modface.ModuleDifference, refs []usage.Reference) []impactedUse {
+	uses := []impactedUse{}
+	for _, c := range listChanges(md, "any") {
+		var matches func(ref usage.Reference) bool
+		switch {
+		case c.Kind == "changed-module-path":
+			matches = func(ref usage.Reference) bool { return true }
+		case c.Kind == "removed-package":
+			matches = func(ref usage.Reference) bool { return ref.Package == c.Package }
+		case c.Old != nil && c.Kind != "deprecated-func" && c.Kind != "deprecated-method":
+			id := c.Old.ID()
+			matches = func(ref usage.Reference) bool { return ref.Package == c.Package && ref.ID == id }
+		default:
+			continue
+		}
+
+		use := impactedUse{change: c}
+		for _, ref := range refs {
+			if matches(ref) {
+				use.refs = append(use.refs, ref)
+			}
+		}
+		if len(use.refs) > 0 {
+			uses = append(uses, use)
+		}
+	}
+	return uses
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/gover/pkg/usage"
+)
+
+func TestImpactedUses(t *testing.T) {
+	foo := modface.FuncSignature{Name: "Foo"}
+	fooChanged := modface.FuncSignature{Name: "Foo", Params: modface.TypeList{{Name: "int"}}}
+	bar := modface.FuncSignature{Name: "Bar"}
+	barDeprecated := modface.FuncSignature{Name: "Bar", Deprecation: "use Foo instead."}
+	baz := modface.FuncSignature{Name: "Baz"}
+	qux := modface.FuncSignature{Name: "Qux"}
+
+	module := func(path string, packages map[string]modface.PackageInterface) *modface.Module {
+		return &modface.Module{Path: path, Packages: packages, Stability: map[string]modface.Stability{}}
+	}
+	old := module("example.com/m", map[string]modface.PackageInterface{
+		"example.com/m/a":    {foo.ID(): foo, bar.ID(): bar, baz.ID(): baz},
+		"example.com/m/gone": {qux.ID(): qux},
+	})
+	current := module("example.com/m", map[string]modface.PackageInterface{
+		"example.com/m/a":   {fooChanged.ID(): fooChanged, barDeprecated.ID(): barDeprecated},
+		"example.com/m/new": {qux.ID(): qux},
+	})
+
+	ref := func(pkgname, id string, line int) usage.Reference {
+		return usage.Reference{Package: pkgname, ID: id, Pos: token.Position{Filename: "main.go", Line: line}}
+	}
+	refs := []usage.Reference{
+		ref("example.com/m/a", foo.ID(), 1),
+		ref("example.com/m/a", bar.ID(), 2),
+		ref("example.com/m/a", baz.ID(), 3),
+		ref("example.com/m/a", baz.ID(), 4),
+		ref("example.com/m/gone", qux.ID(), 5),
+	}
+
+	// describe returns the kind of each impacted change with the lines of its references
+	describe := func(uses []impactedUse) []string {
+		descs := []string{}
+		for _, use := range uses {
+			lines := []int{}
+			for _, ref := range use.refs {
+				lines = append(lines, ref.Pos.Line)
+			}
+			descs = append(descs, fmt.Sprintf("%s %s %v", use.change.Kind, use.change.Package, lines))
+		}
+		return descs
+	}
+
+	tests := []struct {
+		name string
+		md   *modface.ModuleDifference
+		refs []usage.Reference
+		want []string
+	}{
+		{
+			name: "removed and changed exports",
+			md:   modface.Diff(old, current),
+			refs: refs,
+			want: []string{
+				"removed-func example.com/m/a [3 4]",
+				"changed-signature example.com/m/a [1]",
+				"removed-package example.com/m/gone [5]",
+			},
+		},
+		{
+			name: "no references",
+			md:   modface.Diff(old, current),
+			refs: nil,
+			want: []string{},
+		},
+		{
+			name: "unrelated references",
+			md:   modface.Diff(old, current),
+			refs: []usage.Reference{ref("example.com/m/a", bar.ID(), 2), ref("example.com/other", foo.ID(), 6)},
+			want: []string{},
+		},
+		{
+			name: "changed module path",
+			md:   modface.Diff(old, module("example.com/m/v2", old.Packages)),
+			refs: refs[:2],
+			want: []string{"changed-module-path example.com/m/v2 [1 2]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describe(impactedUses(tt.md, tt.refs)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("impactedUses() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure"`
+	Skipped   *junitSkipped `xml:"skipped"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJunit writes module differences as JUnit XML with a test suite for each package and a test
+// case for each change. Breaking changes fail, allowed breaking changes are skipped, and other
+// changes pass. Packages of the current module without changes are written as empty, passing
+// suites. The repodir is the path of the module root relative to the repository root.
+func writeJunit(w io.Writer, md *modface.ModuleDifference, packages []string, level, repodir string) error {
+	report := junitTestSuites{Name: "gover"}
+
+	// list a suite for every package of the current module and every package with changes
+	changes := listChanges(md, level)
+	pkgnames := append([]string{}, packages...)
+	for _, c := range changes {
+		pkgnames = append(pkgnames, c.Package)
+	}
+	sort.Strings(pkgnames)
+	suites := make(map[string]int)
+	for _, pkgname := range pkgnames {
+		if _, found := suites[pkgname]; !found {
+			suites[pkgname] = len(report.Suites)
+			report.Suites = append(report.Suites, junitTestSuite{Name: pkgname})
+		}
+	}
+
+	for _, c := range changes {
+		suite := &report.Suites[suites[c.Package]]
+
+		testcase := junitTestCase{
+			Name:      c.Kind,
+			ClassName: c.Package,
+			SystemOut: c.Message(),
+		}
+		if face := c.Export(); face != nil {
+			testcase.Name = c.Kind + " " + exportName(face)
+		}
+		if file, line := c.Position(); file != "" {
+			testcase.File = path.Join(repodir, file)
+			testcase.Line = line
+		}
+
+		if c.Breaking {
+			testcase.Failure = &junitFailure{Message: c.Message(), Type: c.Kind}
+			suite.Failures++
+			report.Failures++
+		} else if c.Allowed != "" {
+			testcase.Skipped = &junitSkipped{Message: "allowed: " + c.Allowed}
+			suite.Skipped++
+			report.Skipped++
+		}
+		suite.Tests++
+		report.Tests++
+		suite.Cases = append(suite.Cases, testcase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
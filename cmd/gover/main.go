@@ -1,33 +1,100 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/dgravesa/minicli"
 )
 
 func main() {
 	var modpath string
+	var jobs int
+	var timeout time.Duration
+	cfg := new(config)
+
+	// cancel on the first interrupt so that commands stop and remove their temporary files,
+	// and exit immediately on the second
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupted := make(chan os.Signal, 2)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupted
+		cancel()
+		<-interrupted
+		os.Exit(130)
+	}()
 
 	cli := minicli.New()
+	cmds := newConfiguredCmds(ctx, &modpath, &jobs, &timeout, cfg)
+
+	// register command with defaults from project configuration
+	register := func(name, help string, cmd minicli.CmdImpl) {
+		cli.Cmd(name, help, cmds.wrap(name, cmd))
+	}
 
 	cli.Flags("", "", func(flags *flag.FlagSet) {
 		flags.StringVar(&modpath, "C", ".", "path to module")
+		flags.IntVar(&jobs, "j", 0, "number of packages to parse concurrently (default GOMAXPROCS)")
+		flags.DurationVar(&timeout, "timeout", 0, "stop a command that runs longer than this duration")
 	})
 
-	cli.Func("print", "print module interface", makePrintFunc(&modpath))
+	register("print", "print module interface", newPrintCmd(&modpath, cfg))
+
+	register("diff", "compare module interface changes to previous version",
+		newDiffCmd(&modpath, cfg))
+
+	register("tag", "tag with a suggested version", newTagCmd(&modpath, cfg))
+
+	register("bisect", "find the first-parent commit that introduced a breaking change",
+		newBisectCmd(&modpath, cfg))
+
+	register("changelog", "generate a changelog section from module interface changes",
+		newChangelogCmd(&modpath, cfg))
 
-	cli.Cmd("diff", "compare module interface changes to previous version",
-		newDiffCmd(&modpath))
+	register("history", "audit the interface changes of every version",
+		newHistoryCmd(&modpath, cfg))
 
-	// TODO: cowardly removing for now, needs more work to be safer
-	// cli.Cmd("tag", "tag with a suggested version", newTagCmd(&modpath))
+	register("impact", "find uses of removed or changed exports in consumer modules",
+		newImpactCmd(&modpath, cfg))
 
-	cli.Func("suggest", "suggest a new semantic version", makeSuggestFunc(&modpath))
+	register("report", "generate an HTML report of the module interface",
+		newReportCmd(&modpath, cfg))
 
-	if err := cli.Exec(); err != nil {
+	register("since", "report the version each export was added and last changed",
+		newSinceCmd(&modpath, cfg))
+
+	register("usage", "count references to each export within the module and its consumers",
+		newUsageCmd(&modpath, cfg))
+
+	register("verify-tags", "verify existing version tags against semantic versioning rules",
+		newVerifyTagsCmd(&modpath, cfg))
+
+	register("suggest", "suggest a new semantic version", newSuggestCmd(&modpath, cfg))
+
+	cli.Cmd("config", "print the effective project configuration", newConfigCmd(cmds))
+
+	cli.Cmd("cache", "manage the cache of parsed module interfaces", nil)
+	cli.Cmd("cache clean", "remove cached module interfaces", newCacheCleanCmd())
+	cli.Cmd("cache info", "print the location and size of the cache", newCacheInfoCmd())
+
+	err := cli.Exec()
+	switch {
+	case err == nil:
+		return
+	case ctx.Err() != nil:
+		fmt.Fprintln(os.Stderr, "interrupted")
+		os.Exit(130)
+	case errors.Is(err, context.DeadlineExceeded):
+		fmt.Fprintf(os.Stderr, "timed out after %s\n", timeout)
+		os.Exit(1)
+	default:
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+// writeMarkdown renders module differences as a compact Markdown report suitable for a pull request
+// comment. At most limit changes are detailed; any further changes are summarized in a footer.
+// The suggestion is the suggested next version, or a description of why there is none.
+func writeMarkdown(md *modface.ModuleDifference, level, suggestion string, limit int) string {
+	changes := listChanges(md, level)
+
+	var sb strings.Builder
+
+	// summary line
+	status := "no API changes"
+	if md.Breaking() {
+		status = "breaking API changes detected"
+	} else if md.Any() {
+		status = "compatible API changes detected"
+	}
+	sb.WriteString(fmt.Sprintf("### gover: %s\n\n", status))
+	sb.WriteString(fmt.Sprintf("Suggested next version: **%s**\n", suggestion))
+	if len(changes) == 0 {
+		return sb.String()
+	}
+
+	// per-package counts
+	pkgnames, counts := countChanges(changes)
+	sb.WriteString("\n| Package | Added | Removed | Changed | Deprecated |\n")
+	sb.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+	for _, pkgname := range pkgnames {
+		pc := counts[pkgname]
+		sb.WriteString(fmt.Sprintf("| `%s` | %d | %d | %d | %d |\n", pkgname,
+			pc.Added, pc.Removed, pc.Changed, pc.Deprecated))
+	}
+
+	// collapsible details per package, up to limit changes
+	shown := 0
+	for _, pkgname := range pkgnames {
+		if shown >= limit {
+			break
+		}
+		total := counts[pkgname].Total()
+		sb.WriteString(fmt.Sprintf("\n<details>\n<summary><code>%s</code>: %s</summary>\n\n",
+			pkgname, pluralChanges(total)))
+
+		for _, c := range changes {
+			if c.Package != pkgname {
+				continue
+			} else if shown >= limit {
+				break
+			}
+			shown++
+
+			category := changeCategory(c)
+			title := strings.ToUpper(category[:1]) + category[1:]
+			if face := c.Export(); face != nil {
+				title += fmt.Sprintf(" `%s`", exportName(face))
+			} else {
+				title = c.Message()
+				title = strings.ToUpper(title[:1]) + title[1:]
+			}
+			if c.Breaking {
+				title += " (breaking)"
+			} else if c.Allowed != "" {
+				title += fmt.Sprintf(" (allowed: %s)", c.Allowed)
+			} else if c.Unstable && c.Old != nil {
+				title += " (unstable package)"
+			}
+			sb.WriteString(fmt.Sprintf("**%s**\n", title))
+
+			switch {
+			case c.Old != nil && c.New != nil && changeCategory(c) == "changed":
+				sb.WriteString(fmt.Sprintf("```go\n// old\n%s\n// new\n%s\n```\n", c.Old, c.New))
+			case c.Export() != nil:
+				sb.WriteString(fmt.Sprintf("```go\n%s\n```\n", c.Export()))
+			default:
+				sb.WriteString("\n")
+			}
+		}
+
+		sb.WriteString("</details>\n")
+	}
+
+	if remaining := len(changes) - shown; remaining > 0 {
+		more := "more changes"
+		if remaining == 1 {
+			more = "more change"
+		}
+		sb.WriteString(fmt.Sprintf("\n_… and %d %s_\n", remaining, more))
+	}
+
+	return sb.String()
+}
+
+func pluralChanges(n int) string {
+	if n == 1 {
+		return "1 change"
+	}
+	return fmt.Sprintf("%d changes", n)
+}
@@ -17,7 +17,8 @@ func makeOptsetFlag(flags *flag.FlagSet, name, usage, dflt string, valid ...stri
 		valid: map[string]struct{}{
 			dflt: {},
 		},
-		name: name,
+		name:  name,
+		value: dflt,
 	}
 	for _, v := range valid {
 		os.valid[v] = struct{}{}
@@ -26,11 +27,24 @@ func makeOptsetFlag(flags *flag.FlagSet, name, usage, dflt string, valid ...stri
 	valids := append(valid, dflt)
 	usagestr := fmt.Sprintf(`%s ["%s"]`, usage, strings.Join(valids, `","`))
 
-	flags.StringVar(&os.value, name, dflt, usagestr)
+	flags.Var(os, name, usagestr)
 
 	return os
 }
 
+func (os *optset) String() string {
+	return os.value
+}
+
+// Set implements flag.Value, so that invalid selections are refused when the flag is set.
+func (os *optset) Set(value string) error {
+	if _, ok := os.valid[value]; !ok {
+		return fmt.Errorf("invalid selection for %s: %s", os.name, value)
+	}
+	os.value = value
+	return nil
+}
+
 func (os optset) Value() (string, error) {
 	if _, ok := os.valid[os.value]; !ok {
 		return "", fmt.Errorf("invalid selection for %s: %s", os.name, os.value)
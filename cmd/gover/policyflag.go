@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/dgravesa/gover/pkg/versioning"
+)
+
+type policyFlags struct {
+	name          *optset
+	allowBreaking bool
+}
+
+func makePolicyFlags(flags *flag.FlagSet) *policyFlags {
+	others := []string{}
+	for _, name := range versioning.Names() {
+		if name != versioning.DefaultPolicy {
+			others = append(others, name)
+		}
+	}
+
+	pf := new(policyFlags)
+	pf.name = makeOptsetFlag(flags, "policy", "versioning policy",
+		versioning.DefaultPolicy, others...)
+	flags.BoolVar(&pf.allowBreaking, "allow-breaking", false,
+		"allow breaking changes that the versioning policy would otherwise refuse")
+	return pf
+}
+
+func (pf *policyFlags) Policy() (versioning.Policy, error) {
+	name, err := pf.name.Value()
+	if err != nil {
+		return nil, err
+	}
+	return versioning.New(name, versioning.Options{AllowBreaking: pf.allowBreaking})
+}
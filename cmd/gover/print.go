@@ -1,28 +1,38 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 
-	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/minicli"
 )
 
-func makePrintFunc(moddir *string) func(args []string) error {
-	return func(args []string) error {
-		module, err := modface.ParseModule(*moddir)
-		if err != nil {
-			return err
-		}
+type printCmd struct {
+	modpath *string // injected by main command
+	cfg     *config // injected by main command
+}
 
-		fmt.Println("module", module.Path)
+func newPrintCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &printCmd{modpath: modpath, cfg: cfg}
+}
 
-		for pkgname, pkgface := range module.Packages {
-			fmt.Println("- package", pkgname)
+func (pc *printCmd) SetFlags(flags *flag.FlagSet) {}
 
-			for _, face := range pkgface {
-				fmt.Println("  -", face)
-			}
-		}
+func (pc *printCmd) Exec(args []string) error {
+	module, err := pc.cfg.parseModule(*pc.modpath)
+	if err != nil {
+		return err
+	}
 
-		return nil
+	fmt.Println("module", module.Path)
+
+	for pkgname, pkgface := range module.Packages {
+		fmt.Println("- package", pkgname)
+
+		for _, face := range pkgface {
+			fmt.Println("  -", face)
+		}
 	}
+
+	return nil
 }
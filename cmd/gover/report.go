@@ -0,0 +1,275 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/minicli"
+)
+
+type reportCmd struct {
+	modpath *string // injected by main command
+	cfg     *config // injected by main command
+	output  string
+	compare string
+	link    string
+}
+
+func newReportCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &reportCmd{modpath: modpath, cfg: cfg}
+}
+
+func (rc *reportCmd) SetFlags(flags *flag.FlagSet) {
+	flags.StringVar(&rc.output, "o", "report.html", "file to write the report to")
+	flags.StringVar(&rc.compare, "compare", "", "commit or tag to show differences against")
+	flags.StringVar(&rc.link, "link", "{file}#L{line}",
+		"template for links to source lines in git repositories, with {file} relative to the "+
+			"repository root and {rev} the commit")
+}
+
+// reportExport is an export shown in the report.
+type reportExport struct {
+	ID         string
+	Signature  string
+	Link       string
+	Location   string
+	Deprecated bool
+}
+
+// reportChange is a change shown in the report.
+type reportChange struct {
+	Kind     string
+	Category string
+	Breaking bool
+	Note     string
+	Old      *reportExport
+	New      *reportExport
+}
+
+// reportPackage is a package shown in the report.
+type reportPackage struct {
+	Path      string
+	Anchor    string
+	Stability string
+	Exports   []reportExport
+	Changes   []reportChange
+}
+
+// reportData is the data rendered by the report template.
+type reportData struct {
+	Module     string
+	Compare    string
+	Generated  string
+	Packages   []*reportPackage
+	Categories []string
+	NumChanges int
+}
+
+func (rc *reportCmd) Exec(args []string) error {
+	modpath := *rc.modpath
+	module, err := rc.cfg.parseModule(modpath)
+	if err != nil {
+		return err
+	}
+	// locations are relative to the repository root, and source lines are only linked for modules
+	// in git repositories, whose revisions the link template refers to
+	repodir := strings.TrimSuffix(tagPrefix(modpath), "/")
+	git, err := gitRepo(modpath)
+	linked := err == nil
+	var head string
+	if linked {
+		if head, err = git.Revision(rc.cfg.ctx); err != nil {
+			return err
+		}
+	}
+
+	// exports of the compare version only get links if the template can refer to that revision
+	makeExport := func(face modface.Export, rev string) *reportExport {
+		re := &reportExport{
+			ID:         exportName(face),
+			Signature:  face.String(),
+			Deprecated: face.Deprecated(),
+		}
+		if pos := face.Position(); pos.Filename != "" {
+			file := path.Join(repodir, pos.Filename)
+			re.Location = fmt.Sprintf("%s:%d", file, pos.Line)
+			if linked && (rev == head || strings.Contains(rc.link, "{rev}")) {
+				re.Link = strings.NewReplacer("{file}", file, "{line}", strconv.Itoa(pos.Line),
+					"{rev}", rev).Replace(rc.link)
+			}
+		}
+		return re
+	}
+
+	data := reportData{
+		Module:    module.Path,
+		Compare:   rc.compare,
+		Generated: time.Now().Format(time.RFC1123),
+	}
+	packages := make(map[string]*reportPackage)
+	addPackage := func(pkgname string, stability modface.Stability) *reportPackage {
+		rp, found := packages[pkgname]
+		if !found {
+			rp = &reportPackage{
+				Path:      pkgname,
+				Anchor:    "pkg-" + strings.NewReplacer("/", "-", ".", "-").Replace(pkgname),
+				Stability: stability.String(),
+			}
+			packages[pkgname] = rp
+		}
+		return rp
+	}
+
+	// full module interface
+	for pkgname, pkgface := range module.Packages {
+		rp := addPackage(pkgname, module.PackageStability(pkgname))
+		for _, face := range sortedExports(pkgface) {
+			rp.Exports = append(rp.Exports, *makeExport(face, head))
+		}
+	}
+
+	// differences against compare version
+	if rc.compare != "" {
+		moduleDifference, err := gover.Diff(rc.cfg.ctx, modpath, rc.compare, rc.cfg.options()...)
+		if err != nil {
+			return err
+		}
+
+		categories := make(map[string]bool)
+		for _, c := range listChanges(moduleDifference, "any") {
+			stability := modface.Stable
+			if c.Unstable {
+				stability = modface.Unstable
+			}
+			rp := addPackage(c.Package, stability)
+			change := reportChange{
+				Kind:     c.Kind,
+				Category: changeCategory(c),
+				Breaking: c.Breaking,
+			}
+			if c.Allowed != "" {
+				change.Note = "allowed: " + c.Allowed
+			} else if c.Unstable && c.Old != nil {
+				change.Note = "unstable package"
+			}
+			if c.Old != nil {
+				change.Old = makeExport(c.Old, versionTag(modpath, rc.compare))
+			}
+			if c.New != nil {
+				change.New = makeExport(c.New, head)
+			}
+			if c.Old == nil && c.New == nil {
+				change.Note = c.Message()
+			}
+			rp.Changes = append(rp.Changes, change)
+			categories[change.Category] = true
+			data.NumChanges++
+		}
+		for category := range categories {
+			data.Categories = append(data.Categories, category)
+		}
+		sort.Strings(data.Categories)
+	}
+
+	for _, rp := range packages {
+		data.Packages = append(data.Packages, rp)
+	}
+	sort.Slice(data.Packages, func(i, j int) bool {
+		return data.Packages[i].Path < data.Packages[j].Path
+	})
+
+	f, err := os.Create(rc.output)
+	if err != nil {
+		return err
+	}
+	if err := reportTemplate.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Module}} API report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; display: flex; color: #24292f; }
+nav { width: 22em; height: 100vh; overflow-y: auto; position: sticky; top: 0; padding: 1em; background: #f6f8fa; border-right: 1px solid #d0d7de; box-sizing: border-box; }
+nav a { display: block; font-family: monospace; font-size: 0.85em; padding: 0.15em 0; color: #0969da; text-decoration: none; word-break: break-all; }
+main { flex: 1; padding: 1em 2em; min-width: 0; }
+h2 { font-family: monospace; font-size: 1.1em; border-bottom: 1px solid #d0d7de; padding-bottom: 0.3em; }
+code, td.sig { font-family: monospace; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1em; }
+th, td { text-align: left; padding: 0.3em 0.6em; border: 1px solid #d0d7de; vertical-align: top; font-size: 0.9em; }
+th { background: #f6f8fa; }
+.badge { font-size: 0.75em; padding: 0.1em 0.4em; border-radius: 0.8em; background: #eaeef2; margin-left: 0.4em; }
+.breaking { background: #ffebe9; }
+.added td.new { background: #dafbe1; }
+.removed td.old { background: #ffebe9; }
+.changed td.old, .changed td.new, .deprecated td.new { background: #fff8c5; }
+.loc { color: #57606a; font-size: 0.8em; display: block; }
+#filters label { margin-right: 1em; }
+</style>
+</head>
+<body>
+<nav>
+<strong>Packages</strong>
+{{range .Packages}}<a href="#{{.Anchor}}">{{.Path}}</a>
+{{end}}</nav>
+<main>
+<h1><code>{{.Module}}</code></h1>
+<p>Generated {{.Generated}}.{{if .Compare}} {{.NumChanges}} changes since <code>{{.Compare}}</code>.{{end}}</p>
+{{if .Compare}}<p id="filters">Show:
+{{range .Categories}}<label><input type="checkbox" value="{{.}}" checked> {{.}}</label>
+{{end}}<label><input type="checkbox" id="breaking-only"> breaking only</label>
+</p>{{end}}
+{{range .Packages}}<section id="{{.Anchor}}">
+<h2>{{.Path}}{{if eq .Stability "unstable"}}<span class="badge">unstable</span>{{end}}</h2>
+{{if .Changes}}<table class="changes">
+<tr><th>Change</th><th>Old</th><th>New</th></tr>
+{{range .Changes}}<tr class="change {{.Category}}{{if .Breaking}} breaking{{end}}" data-category="{{.Category}}" data-breaking="{{.Breaking}}">
+<td>{{.Kind}}{{if .Breaking}}<span class="badge">breaking</span>{{end}}{{if .Note}}<span class="loc">{{.Note}}</span>{{end}}</td>
+<td class="sig old">{{with .Old}}{{.Signature}}{{if .Link}}<a class="loc" href="{{.Link}}">{{.Location}}</a>{{else}}<span class="loc">{{.Location}}</span>{{end}}{{end}}</td>
+<td class="sig new">{{with .New}}{{.Signature}}{{if .Link}}<a class="loc" href="{{.Link}}">{{.Location}}</a>{{end}}{{end}}</td>
+</tr>
+{{end}}</table>{{end}}
+{{if .Exports}}<table class="exports">
+<tr><th>Export</th><th>Signature</th></tr>
+{{range .Exports}}<tr>
+<td><code>{{.ID}}</code>{{if .Deprecated}}<span class="badge">deprecated</span>{{end}}</td>
+<td class="sig">{{.Signature}}{{if .Link}}<a class="loc" href="{{.Link}}">{{.Location}}</a>{{end}}</td>
+</tr>
+{{end}}</table>{{else}}<p>Package is not part of the current module interface.</p>{{end}}
+</section>
+{{end}}</main>
+<script>
+(function() {
+  var boxes = document.querySelectorAll("#filters input[value]");
+  var breakingOnly = document.getElementById("breaking-only");
+  function update() {
+    var shown = {};
+    boxes.forEach(function(box) { shown[box.value] = box.checked; });
+    document.querySelectorAll("tr.change").forEach(function(row) {
+      var visible = shown[row.dataset.category] &&
+        (!breakingOnly.checked || row.dataset.breaking === "true");
+      row.style.display = visible ? "" : "none";
+    });
+  }
+  boxes.forEach(function(box) { box.addEventListener("change", update); });
+  if (breakingOnly) { breakingOnly.addEventListener("change", update); }
+})();
+</script>
+</body>
+</html>
+`))
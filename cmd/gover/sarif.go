@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+// changeDescriptions describes each kind of change for report rules.
+var changeDescriptions = map[string]string{
+	"changed-module-path": "Module path changed",
+	"removed-package":     "Package removed",
+	"added-package":       "Package added",
+	"removed-func":        "Function removed",
+	"added-func":          "Function added",
+	"removed-method":      "Method removed",
+	"added-method":        "Method added",
+	"changed-signature":   "Signature changed",
+	"deprecated-func":     "Function deprecated",
+	"deprecated-method":   "Method deprecated",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	DefaultConfiguration sarifRuleDefaults `json:"defaultConfiguration"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+var sarifLevelRank = map[string]int{
+	"note":    1,
+	"warning": 2,
+	"error":   3,
+}
+
+// sarifLevel returns the SARIF level for a change.
+// Breaking changes are errors, allowed breaking changes and deprecations are warnings, and other
+// changes are notes.
+func sarifLevel(c change) string {
+	if c.Breaking {
+		return "error"
+	} else if c.Allowed != "" || strings.HasPrefix(c.Kind, "deprecated-") {
+		return "warning"
+	}
+	return "note"
+}
+
+// sarifKind returns the SARIF logical location kind of an export.
+func sarifKind(face modface.Export) string {
+	if exportKind(face) == "method" {
+		return "member"
+	}
+	return "function"
+}
+
+// writeSarif writes module differences as a SARIF log with a result for each change.
+// The repodir is the path of the module root relative to the repository root.
+func writeSarif(w io.Writer, md *modface.ModuleDifference, level, repodir string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gover",
+			InformationURI: "https://github.com/dgravesa/gover",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	ruleLevels := make(map[string]string)
+	for _, c := range listChanges(md, level) {
+		result := sarifResult{
+			RuleID:  c.Kind,
+			Level:   sarifLevel(c),
+			Message: sarifMessage{Text: c.Message()},
+		}
+
+		location := sarifLocation{}
+		if file, line := c.Position(); file != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: path.Join(repodir, file), URIBaseID: "%SRCROOT%"},
+				Region:           sarifRegion{StartLine: line},
+			}
+		}
+		if face := c.Export(); face != nil {
+			location.LogicalLocations = []sarifLogicalLocation{
+				{FullyQualifiedName: qualifiedName(c.Package, face), Kind: sarifKind(face)},
+			}
+		} else {
+			location.LogicalLocations = []sarifLogicalLocation{
+				{FullyQualifiedName: c.Package, Kind: "namespace"},
+			}
+		}
+		result.Locations = []sarifLocation{location}
+		run.Results = append(run.Results, result)
+
+		// rule default level is the most severe level of any of its results
+		if sarifLevelRank[result.Level] >= sarifLevelRank[ruleLevels[c.Kind]] {
+			ruleLevels[c.Kind] = result.Level
+		}
+	}
+
+	for kind, ruleLevel := range ruleLevels {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   kind,
+			ShortDescription:     sarifMessage{Text: changeDescriptions[kind]},
+			DefaultConfiguration: sarifRuleDefaults{Level: ruleLevel},
+		})
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
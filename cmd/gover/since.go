@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/minicli"
+)
+
+type sinceCmd struct {
+	modpath *string // injected by main command
+	cfg     *config // injected by main command
+	format  *optset
+}
+
+func newSinceCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &sinceCmd{modpath: modpath, cfg: cfg}
+}
+
+func (sc *sinceCmd) SetFlags(flags *flag.FlagSet) {
+	sc.format = makeOptsetFlag(flags, "format", "output format", "list", "json", "markdown")
+}
+
+// sinceEntry is the JSON representation of an export's history.
+type sinceEntry struct {
+	Package     string `json:"package"`
+	ID          string `json:"id"`
+	Signature   string `json:"signature"`
+	Added       string `json:"added"`
+	LastChanged string `json:"lastChanged"`
+}
+
+func (sc *sinceCmd) Exec(args []string) error {
+	format, err := sc.format.Value()
+	if err != nil {
+		return err
+	}
+
+	modpath := *sc.modpath
+	versions, err := gover.Versions(sc.cfg.ctx, modpath)
+	if err != nil {
+		return err
+	} else if len(versions) == 0 {
+		return fmt.Errorf("no versions found")
+	}
+
+	history := []modface.VersionedModule{}
+	err = gover.ParseRevisions(sc.cfg.ctx, modpath, versions, func(version string, module *modface.Module) error {
+		history = append(history, modface.VersionedModule{Version: version, Module: module})
+		return nil
+	}, sc.cfg.options()...)
+	if err != nil {
+		return err
+	}
+
+	// flatten annotations in deterministic order
+	entries := []sinceEntry{}
+	for pkgname, pkghistory := range modface.Since(history) {
+		for _, eh := range pkghistory {
+			entries = append(entries, sinceEntry{
+				Package:     pkgname,
+				ID:          eh.Export.ID(),
+				Signature:   eh.Export.String(),
+				Added:       eh.Added,
+				LastChanged: eh.LastChanged,
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Package != entries[j].Package {
+			return entries[i].Package < entries[j].Package
+		}
+		return entries[i].ID < entries[j].ID
+	})
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "markdown":
+		fmt.Print(sinceMarkdown(versions[len(versions)-1], entries))
+	default:
+		pkgname := ""
+		for _, e := range entries {
+			if e.Package != pkgname {
+				pkgname = e.Package
+				fmt.Println("- package", pkgname)
+			}
+			fmt.Printf("  - %s (added %s, last changed %s)\n", e.Signature, e.Added, e.LastChanged)
+		}
+	}
+
+	return nil
+}
+
+// sinceMarkdown renders a Markdown API index of export histories.
+func sinceMarkdown(version string, entries []sinceEntry) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# API index (%s)\n", version))
+
+	pkgname := ""
+	for _, e := range entries {
+		if e.Package != pkgname {
+			pkgname = e.Package
+			sb.WriteString(fmt.Sprintf("\n## `%s`\n\n", pkgname))
+			sb.WriteString("| Export | Added | Last changed |\n")
+			sb.WriteString("| --- | --- | --- |\n")
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %s |\n", e.Signature, e.Added, e.LastChanged))
+	}
+
+	return sb.String()
+}
@@ -1,38 +1,40 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+
+	"github.com/dgravesa/gover/pkg/gover"
+	"github.com/dgravesa/minicli"
 )
 
-func makeSuggestFunc(modpath *string) func(args []string) error {
-	return func(args []string) error {
-		suggestedVersion := "v0.1.0"
-		modpath := *modpath
-
-		versions, err := listVersions(modpath)
-		if err != nil {
-			return err
-		}
-
-		if len(versions) > 0 {
-			// determine an appropriate next version based on module differences
-			latestVersion := versions[len(versions)-1]
-			moduleDifference, err := diff(modpath, latestVersion)
-			if err != nil {
-				return err
-			}
-
-			if moduleDifference.Breaking() {
-				suggestedVersion = suggestVersion(latestVersion, "breaking")
-			} else if moduleDifference.Any() {
-				suggestedVersion = suggestVersion(latestVersion, "feature")
-			} else {
-				suggestedVersion = suggestVersion(latestVersion, "bugfix")
-			}
-		}
-
-		fmt.Println(suggestedVersion)
+type suggestCmd struct {
+	modpath *string // injected by main command
+	cfg     *config // injected by main command
+	policy  *policyFlags
+}
+
+func newSuggestCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &suggestCmd{modpath: modpath, cfg: cfg}
+}
+
+func (sc *suggestCmd) SetFlags(flags *flag.FlagSet) {
+	sc.policy = makePolicyFlags(flags)
+}
 
+func (sc *suggestCmd) Exec(args []string) error {
+	policy, err := sc.policy.Policy()
+	if err != nil {
 		return err
 	}
+
+	suggestedVersion, err := gover.Suggest(sc.cfg.ctx, *sc.modpath,
+		sc.cfg.options(gover.WithPolicy(policy))...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(suggestedVersion)
+
+	return nil
 }
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiReverse = "\x1b[7m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// palette colors terminal output, or leaves it plain if color is disabled.
+type palette struct {
+	enabled bool
+}
+
+// newPalette returns a palette for a color mode of "always", "never", or "auto".
+// In auto mode, color is enabled if stdout is a terminal and NO_COLOR is not set.
+func newPalette(mode string) palette {
+	switch mode {
+	case "always":
+		return palette{enabled: true}
+	case "never":
+		return palette{enabled: false}
+	}
+
+	if _, set := os.LookupEnv("NO_COLOR"); set {
+		return palette{enabled: false}
+	}
+	fi, err := os.Stdout.Stat()
+	return palette{enabled: err == nil && fi.Mode()&os.ModeCharDevice != 0}
+}
+
+func (p palette) paint(code, s string) string {
+	if !p.enabled || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// highlight paints a line in color, with the differing middle part emphasized.
+func (p palette) highlight(color, prefix, middle, suffix string) string {
+	if !p.enabled {
+		return prefix + middle + suffix
+	}
+	return p.paint(color, prefix) + p.paint(color+ansiReverse, middle) + p.paint(color, suffix)
+}
+
+// splitDifference splits two strings into a common prefix and suffix and the differing parts.
+// The split points are moved outward so that identifiers are not broken.
+func splitDifference(a, b string) (prefix, amid, bmid, suffix string) {
+	isIdent := func(r byte) bool {
+		return r == '_' || unicode.IsLetter(rune(r)) || unicode.IsDigit(rune(r))
+	}
+
+	// splitsIdent returns true if splitting s at i would break an identifier
+	splitsIdent := func(s string, i int) bool {
+		return i > 0 && i < len(s) && isIdent(s[i-1]) && isIdent(s[i])
+	}
+
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	for start > 0 && (splitsIdent(a, start) || splitsIdent(b, start)) {
+		start--
+	}
+
+	end := 0
+	for end < len(a)-start && end < len(b)-start && a[len(a)-1-end] == b[len(b)-1-end] {
+		end++
+	}
+	for end > 0 && (splitsIdent(a, len(a)-end) || splitsIdent(b, len(b)-end)) {
+		end--
+	}
+
+	return a[:start], a[start : len(a)-end], b[start : len(b)-end], a[len(a)-end:]
+}
+
+// printDiff prints module differences in a unified diff style.
+// Removals are red, additions are green, and changes are yellow with the differing parts
+// of signatures emphasized.
+func printDiff(moduleDifference *modface.ModuleDifference, level string, p palette) {
+	type difference interface {
+		Any() bool
+		Breaking() bool
+		AnyAllowed() bool
+	}
+
+	meetsLevel := func(d difference) bool {
+		if level == "any" && d.Any() {
+			return true
+		} else if level == "breaking" && (d.Breaking() || d.AnyAllowed()) {
+			return true
+		}
+		return false
+	}
+
+	// mark allowed breaking changes with their reason
+	allowedSuffix := func(allowed map[string]string, key string) string {
+		if reason, found := allowed[key]; found {
+			return fmt.Sprintf("  (allowed: %s)", reason)
+		}
+		return ""
+	}
+
+	if !meetsLevel(moduleDifference) {
+		return
+	}
+
+	// print module name
+	if !moduleDifference.ModPathsMatch {
+		fmt.Println(p.paint(ansiRed, "- module "+moduleDifference.OldModPath))
+		fmt.Println(p.paint(ansiGreen, "+ module "+moduleDifference.ModPath))
+		// do not attempt to print further since everything would be a difference
+		return
+	}
+	fmt.Println(p.paint(ansiBold, "module "+moduleDifference.ModPath))
+
+	// print differences of stable packages, or unstable packages
+	printPackages := func(unstable bool) {
+		// print removals
+		for _, pkgname := range sortedPackageNames(moduleDifference.PackageRemovals) {
+			if moduleDifference.Unstable[pkgname] == unstable {
+				fmt.Println(p.paint(ansiRed, "- package "+pkgname) +
+					allowedSuffix(moduleDifference.Allowed, pkgname))
+			}
+		}
+		// print additions
+		for _, pkgname := range sortedPackageNames(moduleDifference.PackageAdditions) {
+			if moduleDifference.Unstable[pkgname] == unstable {
+				fmt.Println(p.paint(ansiGreen, "+ package "+pkgname))
+			}
+		}
+		// print changes per package
+		pkgnames := []string{}
+		for pkgname := range moduleDifference.PackageChanges {
+			pkgnames = append(pkgnames, pkgname)
+		}
+		sort.Strings(pkgnames)
+		for _, pkgname := range pkgnames {
+			pkgchanges := moduleDifference.PackageChanges[pkgname]
+			if moduleDifference.Unstable[pkgname] != unstable || !meetsLevel(pkgchanges) {
+				continue
+			}
+
+			fmt.Println(p.paint(ansiCyan, "@@ package "+pkgname+" @@"))
+
+			// print package removals
+			for _, face := range sortedExports(pkgchanges.Removals) {
+				fmt.Println(p.paint(ansiRed, "- "+face.String()) +
+					allowedSuffix(pkgchanges.Allowed, face.ID()))
+			}
+			if level == "any" {
+				// print package additions
+				for _, face := range sortedExports(pkgchanges.Additions) {
+					fmt.Println(p.paint(ansiGreen, "+ "+face.String()))
+				}
+			}
+			// print package changes
+			for _, facediff := range sortedExportDifferences(pkgchanges.Changes) {
+				prefix, oldmid, newmid, suffix := splitDifference(facediff.Old.String(),
+					facediff.New.String())
+				fmt.Println(p.highlight(ansiYellow, "- "+prefix, oldmid, suffix) +
+					allowedSuffix(pkgchanges.Allowed, facediff.Old.ID()))
+				fmt.Println(p.highlight(ansiYellow, "+ "+prefix, newmid, suffix))
+			}
+			if level == "any" {
+				// print package deprecations
+				for _, facediff := range sortedExportDifferences(pkgchanges.Deprecations) {
+					fmt.Println(p.paint(ansiYellow, "~ "+facediff.New.String()) + "  (deprecated)")
+				}
+			}
+		}
+	}
+
+	printPackages(false)
+
+	// changes to unstable packages are never breaking, so only print them for any changes
+	if level == "any" && len(moduleDifference.Unstable) > 0 {
+		hasUnstable := false
+		for pkgname := range moduleDifference.Unstable {
+			_, removed := moduleDifference.PackageRemovals[pkgname]
+			_, added := moduleDifference.PackageAdditions[pkgname]
+			_, changed := moduleDifference.PackageChanges[pkgname]
+			hasUnstable = hasUnstable || removed || added || changed
+		}
+		if hasUnstable {
+			fmt.Println(p.paint(ansiBold, "=== unstable packages"))
+			printPackages(true)
+		}
+	}
+}
+
+// printStat prints a summary of the number of changes per package.
+func printStat(moduleDifference *modface.ModuleDifference, level string, p palette) error {
+	changes := listChanges(moduleDifference, level)
+	pkgnames, counts := countChanges(changes)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tADDED\tREMOVED\tCHANGED\tDEPRECATED")
+	total := changeCounts{}
+	for _, pkgname := range pkgnames {
+		pc := counts[pkgname]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", pkgname, pc.Added, pc.Removed, pc.Changed,
+			pc.Deprecated)
+		total.Added += pc.Added
+		total.Removed += pc.Removed
+		total.Changed += pc.Changed
+		total.Deprecated += pc.Deprecated
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d packages: %s, %s, %s, %s\n", len(pkgnames),
+		p.paint(ansiGreen, fmt.Sprintf("%d added", total.Added)),
+		p.paint(ansiRed, fmt.Sprintf("%d removed", total.Removed)),
+		p.paint(ansiYellow, fmt.Sprintf("%d changed", total.Changed)),
+		p.paint(ansiYellow, fmt.Sprintf("%d deprecated", total.Deprecated)))
+	return nil
+}
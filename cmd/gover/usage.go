@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/dgravesa/gover/pkg/usage"
+	"github.com/dgravesa/minicli"
+)
+
+type usageCmd struct {
+	modpath   *string // injected by main command
+	cfg       *config // injected by main command
+	consumers string
+	format    *optset
+	top       int
+}
+
+func newUsageCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &usageCmd{modpath: modpath, cfg: cfg}
+}
+
+func (uc *usageCmd) SetFlags(flags *flag.FlagSet) {
+	flags.StringVar(&uc.consumers, "consumers", "",
+		"comma-separated consumer module directories or module@version in the module cache")
+	uc.format = makeOptsetFlag(flags, "format", "output format", "text", "json")
+	flags.IntVar(&uc.top, "top", 10, "number of exports to list by widest blast radius")
+}
+
+// usageEntry is the JSON representation of the uses of an export.
+type usageEntry struct {
+	Package   string   `json:"package"`
+	ID        string   `json:"id"`
+	Signature string   `json:"signature"`
+	Internal  int      `json:"internal"`
+	External  int      `json:"external"`
+	Inferred  int      `json:"inferred"`
+	Consumers []string `json:"consumers"`
+	name      string
+}
+
+func (uc *usageCmd) Exec(args []string) error {
+	format, err := uc.format.Value()
+	if err != nil {
+		return err
+	}
+
+	modpath := *uc.modpath
+	module, err := uc.cfg.parseModule(modpath)
+	if err != nil {
+		return err
+	}
+
+	// count uses within the module, then by each consumer
+	refs := map[string][]usage.Reference{}
+	if refs[""], err = usage.Scan(uc.cfg.ctx, modpath, module); err != nil {
+		return err
+	}
+	var consumers []consumer
+	if uc.consumers != "" {
+		if consumers, err = parseConsumers(uc.cfg.ctx, uc.consumers); err != nil {
+			return err
+		}
+		consumerRefs, err := scanConsumers(uc.cfg.ctx, consumers, module)
+		if err != nil {
+			return err
+		}
+		for name, crefs := range consumerRefs {
+			refs[name] = crefs
+		}
+	}
+
+	entries := map[string]*usageEntry{}
+	for pkgname, pkgface := range module.Packages {
+		for id, face := range pkgface {
+			entries[pkgname+" "+id] = &usageEntry{
+				Package:   pkgname,
+				ID:        id,
+				Signature: face.String(),
+				Consumers: []string{},
+				name:      exportName(face),
+			}
+		}
+	}
+	for _, c := range append([]consumer{{}}, consumers...) {
+		for _, ref := range refs[c.Name] {
+			e := entries[ref.Package+" "+ref.ID]
+			if ref.Inferred {
+				e.Inferred++
+			}
+			if c.Name == "" {
+				e.Internal++
+				continue
+			}
+			e.External++
+			if n := len(e.Consumers); n == 0 || e.Consumers[n-1] != c.Name {
+				e.Consumers = append(e.Consumers, c.Name)
+			}
+		}
+	}
+
+	sorted := []usageEntry{}
+	for _, e := range entries {
+		sorted = append(sorted, *e)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Package != sorted[j].Package {
+			return sorted[i].Package < sorted[j].Package
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sorted)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tEXPORT\tINTERNAL\tEXTERNAL\tCONSUMERS")
+	for _, e := range sorted {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", e.Package, e.name, e.Internal, e.External,
+			len(e.Consumers))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, e := range sorted {
+		if e.Inferred > 0 {
+			fmt.Println("method references are matched by name and may include calls on other types")
+			break
+		}
+	}
+	if len(consumers) == 0 {
+		return nil
+	}
+
+	// exports that no consumer uses could be unexported without breaking anyone
+	unused := []string{}
+	for _, e := range sorted {
+		if e.External == 0 {
+			unused = append(unused, e.Package+"."+e.name)
+		}
+	}
+	fmt.Printf("\n=== not used by any consumer (%d)\n", len(unused))
+	for _, name := range unused {
+		fmt.Println(name)
+	}
+
+	// exports whose changes would affect the most consumers, then the most references
+	radius := append([]usageEntry{}, sorted...)
+	sort.SliceStable(radius, func(i, j int) bool {
+		if len(radius[i].Consumers) != len(radius[j].Consumers) {
+			return len(radius[i].Consumers) > len(radius[j].Consumers)
+		}
+		return radius[i].External > radius[j].External
+	})
+	fmt.Println("\n=== widest blast radius")
+	for i, e := range radius {
+		if i == uc.top || e.External == 0 {
+			break
+		}
+		fmt.Printf("%s.%s: %d references in %d of %d consumers\n", e.Package, e.name,
+			e.External, len(e.Consumers), len(consumers))
+	}
+
+	return nil
+}
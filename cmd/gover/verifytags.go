@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dgravesa/gover/pkg/versioning"
+	"github.com/dgravesa/minicli"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+type verifyTagsCmd struct {
+	modpath *string // injected by main command
+	cfg     *config // injected by main command
+	errcond *optset
+	policy  *policyFlags
+}
+
+func newVerifyTagsCmd(modpath *string, cfg *config) minicli.CmdImpl {
+	return &verifyTagsCmd{modpath: modpath, cfg: cfg}
+}
+
+func (vc *verifyTagsCmd) SetFlags(flags *flag.FlagSet) {
+	vc.errcond = makeOptsetFlag(flags, "error", "severity of violation to exit with error status code",
+		"error", "warning", "none")
+	vc.policy = makePolicyFlags(flags)
+}
+
+// tagViolation is a problem found with a version tag.
+type tagViolation struct {
+	Severity string
+	Tag      string
+	Check    string
+	Message  string
+}
+
+func (vc *verifyTagsCmd) Exec(args []string) error {
+	errcond, err := vc.errcond.Value()
+	if err != nil {
+		return err
+	}
+	policy, err := vc.policy.Policy()
+	if err != nil {
+		return err
+	}
+
+	violations, err := verifyTags(*vc.modpath, policy, vc.cfg)
+	if err != nil {
+		return err
+	}
+
+	numErrors, numWarnings := 0, 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SEVERITY\tTAG\tCHECK\tMESSAGE")
+	for _, v := range violations {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Severity, v.Tag, v.Check, v.Message)
+		if v.Severity == "error" {
+			numErrors++
+		} else {
+			numWarnings++
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	switch {
+	case errcond == "error" && numErrors > 0, errcond == "warning" && numErrors+numWarnings > 0:
+		return fmt.Errorf("%d errors and %d warnings found", numErrors, numWarnings)
+	}
+	return nil
+}
+
+// verifyTags checks every version tag of the module at modpath for consistency.
+func verifyTags(modpath string, policy versioning.Policy, cfg *config) ([]tagViolation, error) {
+	violations := []tagViolation{}
+
+	// check tag types
+	git, err := gitRepo(modpath)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := git.Output(cfg.ctx, "for-each-ref", "--format=%(refname:short) %(objecttype)",
+		"refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	prefix := tagPrefix(modpath)
+	for _, line := range strings.Split(refs, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !strings.HasPrefix(fields[0], prefix) {
+			continue
+		}
+		if version := strings.TrimPrefix(fields[0], prefix); semver.IsValid(version) && fields[1] != "tag" {
+			violations = append(violations, tagViolation{"warning", version, "lightweight",
+				"version is a lightweight tag, annotated tags are recommended"})
+		}
+	}
+
+	releases, err := listReleases(modpath, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range releases {
+		if r.Err != nil {
+			violations = append(violations, tagViolation{"error", r.Version, "parse", r.Err.Error()})
+			continue
+		}
+
+		// check major version suffix of module path
+		if err := module.Check(r.ModPath, r.Version); err != nil {
+			message := err.Error()
+			if _, pathMajor, _ := module.SplitPathVersion(r.ModPath); pathMajor == "" {
+				message = fmt.Sprintf("module path %s is missing /%s suffix",
+					r.ModPath, semver.Major(r.Version))
+			}
+			violations = append(violations, tagViolation{"error", r.Version, "module-path", message})
+		}
+
+		if r.Previous == "" {
+			continue
+		}
+
+		// check that versions form a line of history
+		isAncestor, err := git.IsAncestor(cfg.ctx, versionTag(modpath, r.Previous),
+			versionTag(modpath, r.Version))
+		if err != nil {
+			return nil, err
+		} else if !isAncestor {
+			violations = append(violations, tagViolation{"warning", r.Version, "ancestry",
+				fmt.Sprintf("%s is not an ancestor of %s", r.Previous, r.Version)})
+		}
+
+		// check that version bump is justified by interface changes
+		change := versioning.Classify(r.Difference)
+		if status, note := auditRelease(r, change, policy); status == "unjustified" || status == "error" {
+			violations = append(violations, tagViolation{"error", r.Version, "semver", note})
+		}
+	}
+
+	// report violations in version order
+	sort.SliceStable(violations, func(i, j int) bool {
+		return semver.Compare(violations[i].Tag, violations[j].Tag) < 0
+	})
+
+	return violations, nil
+}
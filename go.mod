@@ -3,6 +3,8 @@ module github.com/dgravesa/gover
 go 1.14
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/dgravesa/minicli v0.4.1
 	golang.org/x/mod v0.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
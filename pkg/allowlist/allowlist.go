@@ -0,0 +1,170 @@
+// Package allowlist parses lists of intentional breaking changes and applies them to module
+// differences.
+//
+// An allowlist file contains one entry per line of the form:
+//
+//	<package> <export> <expiry> <reason...>
+//
+// The package and export are patterns in the syntax of path.Match. The export is matched against
+// export IDs without the leading dot of receiverless functions, such as Func or Type.Method.
+// An export pattern that matches the empty string, such as *, also allows removal of the package.
+// The expiry is a date of the form YYYY-MM-DD after which the entry no longer applies.
+// The reason is mandatory. Blank lines and lines beginning with # are ignored.
+package allowlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+// DefaultFile is the name of the allowlist file in a module root.
+const DefaultFile = ".gover-allow"
+
+const dateFormat = "2006-01-02"
+
+// Entry allows breaking changes to exports matching a package and export pattern.
+type Entry struct {
+	Package string
+	Export  string
+	Expires time.Time
+	Reason  string
+
+	// Source is the location the entry was read from, for use in warnings.
+	Source string
+}
+
+// Matches returns true if the entry matches an export ID of a package.
+// An empty ID refers to the package itself.
+func (e Entry) Matches(pkgname, id string) bool {
+	pkgMatch, _ := path.Match(e.Package, pkgname)
+	exportMatch, _ := path.Match(e.Export, strings.TrimPrefix(id, "."))
+	return pkgMatch && exportMatch
+}
+
+// Expired returns true if the entry no longer applies at time now.
+func (e Entry) Expired(now time.Time) bool {
+	return !now.Before(e.Expires.AddDate(0, 0, 1))
+}
+
+// List is a list of allowlist entries.
+type List []Entry
+
+// Load reads the allowlist file at filename.
+func Load(filename string) (List, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f, filename)
+}
+
+// LoadDefault reads the DefaultFile in the module root at modpath.
+// A missing file is treated as an empty list.
+func LoadDefault(modpath string) (List, error) {
+	list, err := Load(filepath.Join(modpath, DefaultFile))
+	if os.IsNotExist(err) {
+		return List{}, nil
+	}
+	return list, err
+}
+
+// Parse reads allowlist entries from r.
+// The name is used to identify the source of entries in errors and warnings.
+func Parse(r io.Reader, name string) (List, error) {
+	list := List{}
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		source := fmt.Sprintf("%s:%d", name, lineno)
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("%s: expected <package> <export> <expiry> <reason>", source)
+		}
+		entry, err := NewEntry(fields[0], fields[1], fields[2], strings.Join(fields[3:], " "))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", source, err)
+		}
+		entry.Source = source
+		list = append(list, entry)
+	}
+
+	return list, scanner.Err()
+}
+
+// NewEntry validates and returns an entry.
+func NewEntry(pkgpattern, exportpattern, expiry, reason string) (Entry, error) {
+	if _, err := path.Match(pkgpattern, ""); err != nil {
+		return Entry{}, fmt.Errorf("invalid package pattern %s: %v", pkgpattern, err)
+	}
+	if _, err := path.Match(exportpattern, ""); err != nil {
+		return Entry{}, fmt.Errorf("invalid export pattern %s: %v", exportpattern, err)
+	}
+	expires, err := time.Parse(dateFormat, expiry)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid expiry %s, expected YYYY-MM-DD", expiry)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return Entry{}, fmt.Errorf("missing reason")
+	}
+
+	return Entry{
+		Package: pkgpattern,
+		Export:  exportpattern,
+		Expires: expires,
+		Reason:  reason,
+	}, nil
+}
+
+// Apply allows all breaking differences in md matched by unexpired entries in the list.
+// Warnings are returned for expired entries and for entries which do not match any difference.
+func (l List) Apply(md *modface.ModuleDifference, now time.Time) []string {
+	warnings := []string{}
+
+	for _, entry := range l {
+		if entry.Expired(now) {
+			warnings = append(warnings, fmt.Sprintf("%s: entry for %s %s expired on %s",
+				entry.Source, entry.Package, entry.Export, entry.Expires.Format(dateFormat)))
+			continue
+		}
+
+		matched := false
+		for pkgname := range md.PackageRemovals {
+			if entry.Matches(pkgname, "") {
+				matched = md.Allow(pkgname, "", entry.Reason) || matched
+			}
+		}
+		for pkgname, packdiff := range md.PackageChanges {
+			for id := range packdiff.Removals {
+				if entry.Matches(pkgname, id) {
+					matched = md.Allow(pkgname, id, entry.Reason) || matched
+				}
+			}
+			for id := range packdiff.Changes {
+				if entry.Matches(pkgname, id) {
+					matched = md.Allow(pkgname, id, entry.Reason) || matched
+				}
+			}
+		}
+
+		if !matched {
+			warnings = append(warnings, fmt.Sprintf("%s: entry for %s %s does not match any change",
+				entry.Source, entry.Package, entry.Export))
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,189 @@
+package allowlist
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(dateFormat, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    List
+		wantErr string
+	}{
+		{
+			name: "entries",
+			input: "# comment\n\nexample.com/m Foo 2030-01-31 renamed to Bar\n" +
+				"  example.com/m/* * 2030-02-01 gone \n",
+			want: List{
+				{Package: "example.com/m", Export: "Foo", Expires: date("2030-01-31"),
+					Reason: "renamed to Bar", Source: "allow:3"},
+				{Package: "example.com/m/*", Export: "*", Expires: date("2030-02-01"),
+					Reason: "gone", Source: "allow:4"},
+			},
+		},
+		{
+			name:    "missing reason",
+			input:   "example.com/m Foo 2030-01-31\n",
+			wantErr: "allow:1: expected <package> <export> <expiry> <reason>",
+		},
+		{
+			name:    "too few fields",
+			input:   "\nexample.com/m\n",
+			wantErr: "allow:2: expected <package> <export> <expiry> <reason>",
+		},
+		{
+			name:    "bad date",
+			input:   "example.com/m Foo 01/31/2030 renamed\n",
+			wantErr: "allow:1: invalid expiry 01/31/2030, expected YYYY-MM-DD",
+		},
+		{
+			name:    "bad pattern",
+			input:   "example.com/[m Foo 2030-01-31 renamed\n",
+			wantErr: "allow:1: invalid package pattern example.com/[m",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(strings.NewReader(tt.input), "allow")
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: Parse() error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%s: Parse() error = %v", tt.name, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Parse() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewEntryMissingReason(t *testing.T) {
+	if _, err := NewEntry("example.com/m", "Foo", "2030-01-31", " "); err == nil {
+		t.Error("NewEntry() with blank reason succeeded, want error")
+	}
+}
+
+func TestExpired(t *testing.T) {
+	entry := Entry{Expires: date("2030-01-31")}
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{date("2030-01-30"), false},
+		{date("2030-01-31"), false},
+		{date("2030-01-31").Add(23*time.Hour + 59*time.Minute), false},
+		{date("2030-02-01"), true},
+		{date("2030-03-01"), true},
+	}
+
+	for _, tt := range tests {
+		if got := entry.Expired(tt.now); got != tt.want {
+			t.Errorf("Expired(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pkgpattern, exportpattern string
+		pkgname, id               string
+		want                      bool
+	}{
+		{"example.com/m", "Foo", "example.com/m", ".Foo", true},
+		{"example.com/m", "Foo", "example.com/m", "Foo", true},
+		{"example.com/m", "Foo", "example.com/m", ".Bar", false},
+		{"example.com/m", "Foo", "example.com/m/sub", ".Foo", false},
+		{"example.com/m", "*", "example.com/m", ".Foo", true},
+		{"example.com/m", "*", "example.com/m", "", true},
+		{"example.com/m", "Foo", "example.com/m", "", false},
+		{"example.com/m", "T.*", "example.com/m", "T.Method", true},
+		{"example.com/m", "*", "example.com/m", "T.Method", true},
+		{"example.com/*", "Foo", "example.com/m", ".Foo", true},
+		{"example.com/*", "Foo", "example.com/m/sub", ".Foo", false},
+	}
+
+	for _, tt := range tests {
+		entry := Entry{Package: tt.pkgpattern, Export: tt.exportpattern}
+		if got := entry.Matches(tt.pkgname, tt.id); got != tt.want {
+			t.Errorf("Entry{%s %s}.Matches(%s, %s) = %v, want %v",
+				tt.pkgpattern, tt.exportpattern, tt.pkgname, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	foo := modface.FuncSignature{Name: "Foo"}
+	bar := modface.FuncSignature{Name: "Bar"}
+	module := func(face modface.PackageInterface) *modface.Module {
+		return &modface.Module{Path: "example.com/m", Packages: map[string]modface.PackageInterface{
+			"example.com/m": face,
+		}, Stability: map[string]modface.Stability{}}
+	}
+	md := modface.Diff(module(modface.PackageInterface{foo.ID(): foo, bar.ID(): bar}),
+		module(modface.PackageInterface{}))
+
+	list := List{
+		{Package: "example.com/m", Export: "Foo", Expires: date("2030-01-31"), Reason: "renamed",
+			Source: "allow:1"},
+		{Package: "example.com/m", Export: "Baz", Expires: date("2030-01-31"), Reason: "renamed",
+			Source: "allow:2"},
+		{Package: "example.com/m", Export: "Bar", Expires: date("2020-01-31"), Reason: "renamed",
+			Source: "allow:3"},
+	}
+	want := []string{
+		"allow:2: entry for example.com/m Baz does not match any change",
+		"allow:3: entry for example.com/m Bar expired on 2020-01-31",
+	}
+
+	if got := list.Apply(md, date("2025-01-01")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() warnings = %q, want %q", got, want)
+	}
+	if !md.AnyAllowed() || !md.Breaking() {
+		t.Errorf("Apply() should allow removal of Foo but not of Bar")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gover-allowlist-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a missing default file is an empty list, but any other missing file is an error
+	if list, err := LoadDefault(dir); err != nil || len(list) != 0 {
+		t.Errorf("LoadDefault() without file = %v, %v, want empty list", list, err)
+	}
+	if _, err := Load(filepath.Join(dir, "allow")); !os.IsNotExist(err) {
+		t.Errorf("Load() of missing file error = %v, want not exist", err)
+	}
+
+	content := "example.com/m Foo 2030-01-31 renamed\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, DefaultFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadDefault(dir)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(list) != 1 || list[0].Source != filepath.Join(dir, DefaultFile)+":1" {
+		t.Errorf("LoadDefault() = %+v, want one entry from %s", list, DefaultFile)
+	}
+}
@@ -0,0 +1,191 @@
+package gover
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+// Cache stores parsed module interfaces by commit, so that revisions which have been parsed
+// before need not be exported and parsed again.
+type Cache struct {
+	Dir string
+}
+
+// CacheInfo describes the contents of a cache.
+type CacheInfo struct {
+	Dir     string
+	Entries int   // number of modules stored for the current schema version
+	Size    int64 // total size in bytes of all stored modules
+	Stale   int   // number of modules stored for other schema versions
+}
+
+// cacheEntry is the stored form of a module.
+type cacheEntry struct {
+	Schema int             `json:"schema"`
+	Module *modface.Module `json:"module"`
+}
+
+// DefaultCache returns the cache in the gover directory of the user cache directory, which is
+// $XDG_CACHE_HOME/gover on Unix systems. The GOVERCACHE environment variable overrides the
+// directory, or disables caching if set to off, in which case DefaultCache returns nil.
+func DefaultCache() (*Cache, error) {
+	dir := os.Getenv("GOVERCACHE")
+	if dir == "off" {
+		return nil, nil
+	} else if dir == "" {
+		userdir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(userdir, "gover")
+	}
+	return &Cache{Dir: dir}, nil
+}
+
+// schemaDir returns the directory that modules of the current schema version are stored in.
+// Modules of other schema versions are kept in sibling directories.
+func (c *Cache) schemaDir() string {
+	return filepath.Join(c.Dir, fmt.Sprintf("modules-v%d", modface.SchemaVersion))
+}
+
+// cacheKey returns the cache key of a module at a commit when parsed in the current build context.
+func cacheKey(modpath, commit string) string {
+	ctxt := build.Default
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%s/%s\x00%s\x00%s", modpath, commit, modface.SchemaVersion,
+		ctxt.GOOS, ctxt.GOARCH, strings.Join(ctxt.BuildTags, ","), runtime.Version())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) filename(key string) string {
+	return filepath.Join(c.schemaDir(), key[:2], key+".json")
+}
+
+// Load returns the module stored for a module path and commit, or nil if there is none.
+func (c *Cache) Load(modpath, commit string) *modface.Module {
+	data, err := ioutil.ReadFile(c.filename(cacheKey(modpath, commit)))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Schema != modface.SchemaVersion {
+		return nil
+	}
+	return entry.Module
+}
+
+// Store stores a module for a module path and commit.
+func (c *Cache) Store(modpath, commit string, module *modface.Module) error {
+	data, err := json.Marshal(cacheEntry{Schema: modface.SchemaVersion, Module: module})
+	if err != nil {
+		return err
+	}
+
+	filename := c.filename(cacheKey(modpath, commit))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	// write atomically, so that concurrent readers never see a partial module
+	f, err := ioutil.TempFile(filepath.Dir(filename), "tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
+
+// staleDirs returns the directories of modules stored for other schema versions.
+func (c *Cache) staleDirs() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, "modules-v*"))
+	if err != nil {
+		return nil, err
+	}
+	stale := []string{}
+	for _, match := range matches {
+		if match != c.schemaDir() {
+			stale = append(stale, match)
+		}
+	}
+	return stale, nil
+}
+
+// CleanStale removes the modules stored for other schema versions, which can no longer be loaded.
+func (c *Cache) CleanStale() error {
+	stale, err := c.staleDirs()
+	if err != nil {
+		return err
+	}
+	for _, dir := range stale {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clean removes all stored modules.
+func (c *Cache) Clean() error {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, "modules-v*"))
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if err := os.RemoveAll(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Info returns a description of the contents of the cache.
+func (c *Cache) Info() (CacheInfo, error) {
+	info := CacheInfo{Dir: c.Dir}
+
+	count := func(dir string, n *int) error {
+		err := filepath.Walk(dir, func(filename string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			} else if !fi.IsDir() && strings.HasSuffix(filename, ".json") {
+				*n++
+				info.Size += fi.Size()
+			}
+			return nil
+		})
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := count(c.schemaDir(), &info.Entries); err != nil {
+		return info, err
+	}
+	stale, err := c.staleDirs()
+	if err != nil {
+		return info, err
+	}
+	for _, dir := range stale {
+		if err := count(dir, &info.Stale); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
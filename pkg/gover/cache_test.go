@@ -0,0 +1,170 @@
+package gover
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+func newTestCache(t *testing.T) *Cache {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "gover-cache-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return &Cache{Dir: dir}
+}
+
+func testModule() *modface.Module {
+	foo := modface.FuncSignature{
+		Name:    "Foo",
+		Params:  modface.TypeList{{Name: "int"}},
+		Results: modface.TypeList{{Name: "error"}},
+		Doc:     "Foo does something.",
+	}
+	return &modface.Module{
+		Path: "example.com/m",
+		Packages: map[string]modface.PackageInterface{
+			"example.com/m":     {foo.ID(): foo},
+			"example.com/m/exp": {},
+		},
+		Stability: map[string]modface.Stability{"example.com/m/exp": modface.Unstable},
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	c := newTestCache(t)
+	module := testModule()
+
+	if got := c.Load(module.Path, "abc123"); got != nil {
+		t.Fatalf("Load() before Store() = %v, want nil", got)
+	}
+	if err := c.Store(module.Path, "abc123", module); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.Load(module.Path, "abc123"); !reflect.DeepEqual(got, module) {
+		t.Errorf("Load() = %#v, want %#v", got, module)
+	}
+	if got := c.Load(module.Path, "def456"); got != nil {
+		t.Errorf("Load() of other commit = %v, want nil", got)
+	}
+	if got := c.Load("example.com/other", "abc123"); got != nil {
+		t.Errorf("Load() of other module = %v, want nil", got)
+	}
+}
+
+func TestCacheStaleSchema(t *testing.T) {
+	c := newTestCache(t)
+	module := testModule()
+
+	// an entry of another schema version at the current location is not loaded
+	filename := c.filename(cacheKey(module.Path, "abc123"))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(cacheEntry{Schema: modface.SchemaVersion + 1, Module: module})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Load(module.Path, "abc123"); got != nil {
+		t.Errorf("Load() of other schema version = %v, want nil", got)
+	}
+
+	// a corrupt entry is not loaded
+	if err := ioutil.WriteFile(filename, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Load(module.Path, "abc123"); got != nil {
+		t.Errorf("Load() of corrupt entry = %v, want nil", got)
+	}
+
+	// storing replaces the entry
+	if err := c.Store(module.Path, "abc123", module); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Load(module.Path, "abc123"); !reflect.DeepEqual(got, module) {
+		t.Errorf("Load() after Store() = %#v, want %#v", got, module)
+	}
+}
+
+// writeStaleEntries writes n entries into the directory of another schema version.
+func writeStaleEntries(t *testing.T, c *Cache, n int) string {
+	t.Helper()
+	dir := filepath.Join(c.Dir, fmt.Sprintf("modules-v%d", modface.SchemaVersion-1), "00")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		filename := filepath.Join(dir, fmt.Sprintf("%02d.json", i))
+		if err := ioutil.WriteFile(filename, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCacheInfoClean(t *testing.T) {
+	c := newTestCache(t)
+	module := testModule()
+
+	info, err := c.Info()
+	if err != nil {
+		t.Fatal(err)
+	} else if want := (CacheInfo{Dir: c.Dir}); info != want {
+		t.Errorf("Info() of empty cache = %+v, want %+v", info, want)
+	}
+
+	staleDir := writeStaleEntries(t, c, 2)
+	for _, commit := range []string{"a", "b", "c"} {
+		if err := c.Store(module.Path, commit, module); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// storing leaves entries of other schema versions in place
+	if _, err := os.Stat(staleDir); err != nil {
+		t.Errorf("Store() removed stale entries: %v", err)
+	}
+	info, err = c.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Entries != 3 || info.Stale != 2 || info.Size == 0 {
+		t.Errorf("Info() = %+v, want 3 entries and 2 stale", info)
+	}
+
+	if err := c.CleanStale(); err != nil {
+		t.Fatal(err)
+	}
+	info, err = c.Info()
+	if err != nil {
+		t.Fatal(err)
+	} else if info.Entries != 3 || info.Stale != 0 {
+		t.Errorf("Info() after CleanStale() = %+v, want 3 entries and none stale", info)
+	}
+
+	writeStaleEntries(t, c, 1)
+	if err := c.Clean(); err != nil {
+		t.Fatal(err)
+	}
+	info, err = c.Info()
+	if err != nil {
+		t.Fatal(err)
+	} else if want := (CacheInfo{Dir: c.Dir}); info != want {
+		t.Errorf("Info() after Clean() = %+v, want %+v", info, want)
+	}
+	if got := c.Load(module.Path, "a"); got != nil {
+		t.Errorf("Load() after Clean() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,43 @@
+package gover
+
+import (
+	"context"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+// Diff returns the differences of the module interface of the working tree at dir from the
+// module interface at a revision of its repository.
+func Diff(ctx context.Context, dir, ref string, opts ...Option) (*modface.ModuleDifference, error) {
+	compareModule, currentModule, err := ParseCompared(ctx, dir, ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return modface.Diff(compareModule, currentModule), nil
+}
+
+// ParseCompared returns the module interfaces compared by Diff: the module interface at a revision
+// of the repository of the module at dir, and the module interface of the working tree.
+func ParseCompared(ctx context.Context, dir, ref string, opts ...Option) (compareModule,
+	currentModule *modface.Module, err error) {
+
+	o := makeOptions(opts)
+	currentDone := make(chan error, 1)
+
+	// parse current module interface while the revision is checked out
+	go func() {
+		var err error
+		currentModule, err = o.parseModule(ctx, dir)
+		currentDone <- err
+	}()
+
+	compareModule, compareErr := ParseRevision(ctx, dir, ref, opts...)
+	currentErr := <-currentDone
+
+	if currentErr != nil {
+		return nil, nil, currentErr
+	} else if compareErr != nil {
+		return nil, nil, compareErr
+	}
+	return compareModule, currentModule, nil
+}
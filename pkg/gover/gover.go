@@ -0,0 +1,165 @@
+// Package gover provides the versioning operations of the gover command for embedding in other
+// tools. Operations on module history read it from the version control system detected for the
+// module by vcs.Detect: a git or Mercurial repository containing the module, or else snapshots of
+// the module in its .gover-snapshots directory. WithVCS supplies the version control system
+// instead, such as for a module kept in a system that is not detected. Tag is only supported for
+// git repositories. Versions falls back to the module proxy for modules without history.
+package gover
+
+import (
+	"errors"
+	"io"
+
+	"github.com/dgravesa/gover/pkg/vcs"
+	"github.com/dgravesa/gover/pkg/versioning"
+)
+
+// ErrNoVersions is returned by operations which require the module to have been versioned.
+var ErrNoVersions = errors.New("no versions found")
+
+// GitError is returned when a git command fails.
+type GitError = vcs.Error
+
+// RefusalError is returned by Tag when the repository is not in a state that may be tagged.
+type RefusalError struct {
+	Reason string
+}
+
+func (e *RefusalError) Error() string {
+	return "refusing to tag: " + e.Reason
+}
+
+// Option configures an operation.
+type Option func(*options)
+
+type options struct {
+	vcs      vcs.VCS
+	cache    *Cache
+	jobs     int
+	exclude  []string
+	unstable []string
+	policy   versioning.Policy
+	branch   string
+	remote   string
+	message  string
+	sign     bool
+	dryRun   bool
+	plan     func(cmdline string)
+	stdin    io.Reader
+}
+
+func makeOptions(opts []Option) *options {
+	o := &options{
+		policy: versioning.GoDefault{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithVCS sets the version control system holding the history of the module, instead of
+// detecting it from the module directory.
+func WithVCS(v vcs.VCS) Option {
+	return func(o *options) {
+		o.vcs = v
+	}
+}
+
+func (o *options) detectVCS(dir string) (vcs.VCS, error) {
+	if o.vcs != nil {
+		return o.vcs, nil
+	}
+	return vcs.Detect(dir)
+}
+
+// WithCache loads module interfaces of revisions from cache instead of parsing them where
+// possible, and stores those that are parsed. A nil cache disables caching.
+func WithCache(cache *Cache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithJobs sets the number of packages parsed concurrently. The default is GOMAXPROCS.
+func WithJobs(jobs int) Option {
+	return func(o *options) {
+		o.jobs = jobs
+	}
+}
+
+// WithExclude leaves packages matching any of the path.Match patterns out of the module interface.
+func WithExclude(patterns ...string) Option {
+	return func(o *options) {
+		o.exclude = append(o.exclude, patterns...)
+	}
+}
+
+// WithUnstable marks packages matching any of the path.Match patterns as unstable, so that
+// changes to them are not breaking.
+func WithUnstable(patterns ...string) Option {
+	return func(o *options) {
+		o.unstable = append(o.unstable, patterns...)
+	}
+}
+
+// WithPolicy sets the versioning policy used to determine new versions.
+// The default is versioning.GoDefault.
+func WithPolicy(policy versioning.Policy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithBranch sets the branch that HEAD must be on for Tag. The default is the branch that the
+// HEAD of the push remote refers to, or else main or master, whichever the repository has.
+func WithBranch(branch string) Option {
+	return func(o *options) {
+		o.branch = branch
+	}
+}
+
+// WithPush makes Tag push the new tag to a remote.
+func WithPush(remote string) Option {
+	return func(o *options) {
+		o.remote = remote
+	}
+}
+
+// WithMessage sets the message of the tag created by Tag.
+// The default is "version X.Y.Z".
+func WithMessage(message string) Option {
+	return func(o *options) {
+		o.message = message
+	}
+}
+
+// WithSign makes Tag create a GPG-signed tag.
+func WithSign() Option {
+	return func(o *options) {
+		o.sign = true
+	}
+}
+
+// WithDryRun makes Tag plan the git commands for the new tag without running them.
+func WithDryRun() Option {
+	return func(o *options) {
+		o.dryRun = true
+	}
+}
+
+// WithPlan calls fn with a shell command line for each git command Tag plans to run to create
+// and push the new tag. Every command is planned before any of them is run.
+func WithPlan(fn func(cmdline string)) Option {
+	return func(o *options) {
+		o.plan = fn
+	}
+}
+
+// WithStdin connects r to the git commands that create and push the new tag, which may prompt
+// for a passphrase or credentials.
+func WithStdin(r io.Reader) Option {
+	return func(o *options) {
+		o.stdin = r
+	}
+}
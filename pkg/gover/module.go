@@ -0,0 +1,164 @@
+package gover
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/gover/pkg/vcs"
+	"golang.org/x/mod/modfile"
+)
+
+// ParseModule parses the module interface of the working tree at dir, leaving out any packages
+// excluded by WithExclude and marking any packages made unstable by WithUnstable.
+func ParseModule(ctx context.Context, dir string, opts ...Option) (*modface.Module, error) {
+	return makeOptions(opts).parseModule(ctx, dir)
+}
+
+func (o *options) parseModule(ctx context.Context, dir string) (*modface.Module, error) {
+	module, err := modface.ParseModuleContext(ctx, dir, o.jobs)
+	if err != nil {
+		return nil, err
+	}
+	o.applyPackageOptions(module)
+	return module, nil
+}
+
+// applyPackageOptions removes excluded packages from module and marks unstable packages.
+func (o *options) applyPackageOptions(module *modface.Module) {
+	for pkgname := range module.Packages {
+		for _, pattern := range o.exclude {
+			if excluded, _ := path.Match(pattern, pkgname); excluded {
+				delete(module.Packages, pkgname)
+				break
+			}
+		}
+		for _, pattern := range o.unstable {
+			if unstable, _ := path.Match(pattern, pkgname); unstable {
+				module.Stability[pkgname] = modface.Unstable
+			}
+		}
+	}
+}
+
+// ParseRevision parses the module interface at a revision of the module repository at dir.
+// A revision which is a version, such as v1.0.0, refers to the version tag of the module, which
+// is prefixed by the module directory for nested modules.
+// The working tree at dir is left untouched.
+func ParseRevision(ctx context.Context, dir, rev string, opts ...Option) (*modface.Module, error) {
+	var module *modface.Module
+	err := ParseRevisions(ctx, dir, []string{rev}, func(_ string, m *modface.Module) error {
+		module = m
+		return nil
+	}, opts...)
+	return module, err
+}
+
+// ParseRevisions calls fn with the module interface parsed at each revision of the module
+// repository at dir in order. The working tree at dir is left untouched.
+func ParseRevisions(ctx context.Context, dir string, revs []string,
+	fn func(rev string, module *modface.Module) error, opts ...Option) error {
+
+	rp, err := NewRevisionParser(ctx, dir, opts...)
+	if err != nil {
+		return err
+	}
+	defer rp.Close()
+
+	for _, rev := range revs {
+		module, err := rp.Parse(ctx, rev)
+		if err != nil {
+			return err
+		}
+		if err := fn(rev, module); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RevisionParser parses module interfaces at arbitrary revisions by exporting each revision of
+// the module into a temporary directory, so that the working tree of the module is left untouched.
+type RevisionParser struct {
+	tmpdir  string
+	vcs     vcs.VCS
+	opts    *options
+	modpath string // module path of the working tree, used to look up cached modules
+}
+
+// NewRevisionParser prepares to parse revisions of the module at dir, using the version control
+// system detected for the module unless one is set with WithVCS. Modules are loaded from and
+// stored in the cache set with WithCache, if any.
+// Close must be called to remove the temporary directory.
+func NewRevisionParser(ctx context.Context, dir string, opts ...Option) (*RevisionParser, error) {
+	o := makeOptions(opts)
+	v, err := o.detectVCS(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpdir, err := ioutil.TempDir(os.TempDir(), "gover-*")
+	if err != nil {
+		return nil, err
+	}
+	rp := &RevisionParser{tmpdir: tmpdir, vcs: v, opts: o}
+	if gomod, err := ioutil.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		rp.modpath = modfile.ModulePath(gomod)
+	}
+	return rp, nil
+}
+
+// Parse parses the module interface at a revision, exporting the revision unless the module
+// interface at its commit is cached. A revision which is a version refers to its version tag.
+func (rp *RevisionParser) Parse(ctx context.Context, rev string) (*modface.Module, error) {
+	rev = tagName(rp.vcs, rev)
+	cache := rp.opts.cache
+	var commit string
+	if cache != nil && rp.modpath != "" {
+		var err error
+		if commit, err = rp.vcs.Resolve(ctx, rev); err != nil {
+			return nil, err
+		}
+	}
+
+	var module *modface.Module
+	if commit != "" {
+		module = cache.Load(rp.modpath, commit)
+	}
+	if module == nil {
+		var err error
+		if module, err = rp.export(ctx, rev); err != nil {
+			return nil, err
+		}
+		if commit != "" {
+			// a cache that cannot be written only costs time
+			cache.Store(rp.modpath, commit, module)
+		}
+	}
+
+	rp.opts.applyPackageOptions(module)
+	return module, nil
+}
+
+// export exports a revision and parses the module interface at that revision.
+func (rp *RevisionParser) export(ctx context.Context, rev string) (*modface.Module, error) {
+	revdir, err := ioutil.TempDir(rp.tmpdir, "rev-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(revdir)
+
+	if err := rp.vcs.Export(ctx, rev, revdir); err != nil {
+		return nil, err
+	}
+	return modface.ParseModuleContext(ctx, revdir, rp.opts.jobs)
+}
+
+// Close removes the temporary directory.
+func (rp *RevisionParser) Close() error {
+	return os.RemoveAll(rp.tmpdir)
+}
@@ -0,0 +1,29 @@
+package gover
+
+import (
+	"context"
+
+	"github.com/dgravesa/gover/pkg/versioning"
+)
+
+// Suggest determines the next version of the module at dir according to the versioning policy,
+// based on the differences of its working tree from the latest version, which in a git repository
+// is the latest version merged into HEAD.
+// The initial version of the policy is suggested if the module has not been versioned yet.
+func Suggest(ctx context.Context, dir string, opts ...Option) (string, error) {
+	o := makeOptions(opts)
+
+	latest, err := latestVersion(ctx, dir, o)
+	if err == ErrNoVersions {
+		return o.policy.Initial(), nil
+	} else if err != nil {
+		return "", err
+	}
+
+	moduleDifference, err := Diff(ctx, dir, latest, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return o.policy.Next(latest, versioning.Classify(moduleDifference))
+}
@@ -0,0 +1,163 @@
+package gover
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/vcs"
+	"github.com/dgravesa/gover/pkg/versions"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// Tag creates an annotated tag for the next version of the module at dir, as suggested by
+// Suggest, and returns the new version. Tag refuses with a RefusalError unless HEAD is a clean
+// checkout of the expected branch which builds on the latest version and is not tagged yet, or if
+// the new version is not valid for the module path or is already tagged locally or on the push
+// remote, which defaults to the upstream remote of the branch or origin. The tag of a nested
+// module is prefixed by the module directory.
+// Tagging is only supported for modules in git repositories.
+func Tag(ctx context.Context, dir string, opts ...Option) (string, error) {
+	o := makeOptions(opts)
+
+	v, err := o.detectVCS(dir)
+	if err != nil {
+		return "", err
+	}
+	git, ok := v.(*vcs.Git)
+	if !ok {
+		return "", fmt.Errorf("tagging is not supported for %s repositories", v.Name())
+	}
+
+	// refuse to tag anything other than a clean checkout of the expected branch
+	if dirty, err := v.Dirty(ctx); err != nil {
+		return "", err
+	} else if dirty {
+		return "", &RefusalError{"working tree has uncommitted changes"}
+	}
+	branch, err := git.Branch(ctx)
+	if err != nil {
+		return "", err
+	}
+	remote := o.remote
+	if remote == "" {
+		if remote, err = git.DefaultRemote(ctx, branch); err != nil {
+			return "", err
+		}
+	}
+	expected := o.branch
+	if expected == "" {
+		if expected, err = git.DefaultBranch(ctx, remote); err != nil {
+			return "", err
+		} else if expected == "" {
+			return "", &RefusalError{"cannot determine the branch to tag, specify one"}
+		}
+	}
+	if branch != expected {
+		return "", &RefusalError{fmt.Sprintf("HEAD is on %s, expected branch %s", branch, expected)}
+	}
+
+	// HEAD must not already be versioned and must build on top of the latest version
+	headTags, err := git.TagsAt(ctx, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	headVersions := versions.Filter(headTags, versions.Options{Prefix: v.TagPrefix()})
+	if len(headVersions) > 0 {
+		return "", &RefusalError{fmt.Sprintf("HEAD is already tagged as %s", tagName(v, headVersions[0]))}
+	}
+	latest, err := latestVersion(ctx, dir, o)
+	if err != nil && err != ErrNoVersions {
+		return "", err
+	} else if err == nil {
+		latestTag := tagName(v, latest)
+		isDescendant, err := git.IsAncestor(ctx, latestTag, "HEAD")
+		if err != nil {
+			return "", err
+		} else if !isDescendant {
+			return "", &RefusalError{fmt.Sprintf("HEAD is not a descendant of %s", latestTag)}
+		}
+	}
+
+	newVersion, err := Suggest(ctx, dir, opts...)
+	if err != nil {
+		return "", err
+	}
+	newTag := tagName(v, newVersion)
+
+	// refuse versions that the module path does not allow, such as v2 without a /v2 suffix
+	gomod, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", err
+	} else if err := module.Check(modfile.ModulePath(gomod), newVersion); err != nil {
+		return "", &RefusalError{err.Error()}
+	}
+
+	// refuse to tag if the version already exists locally or on the remote
+	exists, err := git.HasTag(ctx, newTag)
+	if err != nil {
+		return "", err
+	} else if exists {
+		return "", &RefusalError{fmt.Sprintf("%s already exists", newTag)}
+	}
+	if remote != "" {
+		remoteExists, err := git.RemoteHasTag(ctx, remote, newTag)
+		if err != nil {
+			return "", err
+		} else if remoteExists {
+			return "", &RefusalError{fmt.Sprintf("%s already exists on %s", newTag, remote)}
+		}
+	}
+
+	// plan version tag creation
+	message := o.message
+	if message == "" {
+		message = fmt.Sprintf("version %s", strings.TrimPrefix(newVersion, "v"))
+	}
+	tagFlag := "-a"
+	if o.sign {
+		tagFlag = "-s"
+	}
+	plan := []*exec.Cmd{
+		git.Command(ctx, "tag", tagFlag, newTag, "-m", message),
+	}
+	if o.remote != "" {
+		// push version tag to remote
+		plan = append(plan, git.Command(ctx, "push", o.remote, "refs/tags/"+newTag))
+	}
+
+	// report full plan before executing any of it
+	if o.plan != nil {
+		for _, cmd := range plan {
+			o.plan(formatCmd(cmd))
+		}
+	}
+	if o.dryRun {
+		return newVersion, nil
+	}
+
+	for _, cmd := range plan {
+		cmd.Stdin = o.stdin
+		if _, err := git.Run(ctx, cmd); err != nil {
+			return "", err
+		}
+	}
+
+	return newVersion, nil
+}
+
+// formatCmd returns a command line for cmd that can be pasted into a shell.
+func formatCmd(cmd *exec.Cmd) string {
+	words := []string{cmd.Path}
+	for _, arg := range cmd.Args[1:] {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`*?[]{}()<>|&;#~!") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		words = append(words, arg)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,74 @@
+package gover
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/dgravesa/gover/pkg/vcs"
+	"github.com/dgravesa/gover/pkg/versions"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// Versions returns the versions tagged in the repository of the module at dir in ascending order.
+// The version tags of a nested module are prefixed by the module directory, such as sub/v1.0.0,
+// and the versions are returned without the prefix.
+// If dir is not part of a repository, the versions listed by the module proxy named by GOPROXY
+// are returned instead.
+func Versions(ctx context.Context, dir string, opts ...Option) ([]string, error) {
+	return listVersions(ctx, dir, makeOptions(opts))
+}
+
+func listVersions(ctx context.Context, dir string, o *options) ([]string, error) {
+	v, err := o.detectVCS(dir)
+	if err == nil {
+		tags, err := v.Tags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return versions.Filter(tags, versions.Options{Prefix: v.TagPrefix()}), nil
+	}
+
+	// fall back to module proxy
+	gomod, readErr := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if readErr != nil {
+		return nil, err
+	}
+	proxy := versions.ProxyURL(os.Getenv("GOPROXY"))
+	if proxy == "" {
+		return nil, fmt.Errorf("%v, and GOPROXY does not name a module proxy", err)
+	}
+	return versions.Proxy(ctx, proxy, modfile.ModulePath(gomod), versions.Options{})
+}
+
+// latestVersion returns the greatest version of the module at dir, or ErrNoVersions.
+// In a git repository only versions merged into HEAD are considered, so that the latest version
+// of a maintenance branch is not superseded by versions tagged on other branches.
+func latestVersion(ctx context.Context, dir string, o *options) (string, error) {
+	var vs []string
+	v, err := o.detectVCS(dir)
+	if git, ok := v.(*vcs.Git); err == nil && ok {
+		vs, err = versions.Git(ctx, git.Root,
+			versions.Options{Prefix: git.TagPrefix(), Merged: "HEAD"})
+	} else {
+		vs, err = listVersions(ctx, dir, o)
+	}
+	if err != nil {
+		return "", err
+	} else if len(vs) == 0 {
+		return "", ErrNoVersions
+	}
+	return vs[len(vs)-1], nil
+}
+
+// tagName returns the name of the tag of the module in v for a revision which is a version, or the
+// revision itself otherwise.
+func tagName(v vcs.VCS, rev string) string {
+	if semver.IsValid(rev) {
+		return v.TagPrefix() + rev
+	}
+	return rev
+}
@@ -0,0 +1,198 @@
+package gover
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testRepo is a git repository created for a test.
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "gover-repo-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	r := &testRepo{t: t, dir: dir}
+	r.git("init", "--quiet")
+	return r
+}
+
+// git runs a git command in the repository.
+func (r *testRepo) git(args ...string) {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=gover", "GIT_AUTHOR_EMAIL=gover@example.com",
+		"GIT_COMMITTER_NAME=gover", "GIT_COMMITTER_EMAIL=gover@example.com",
+		"GIT_CONFIG_NOSYSTEM=1", "HOME="+r.dir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// write writes a file in the repository.
+func (r *testRepo) write(name, content string) {
+	r.t.Helper()
+	filename := filepath.Join(r.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// commit commits all changes and tags the commit with each of tags.
+func (r *testRepo) commit(tags ...string) {
+	r.t.Helper()
+	r.git("add", "-A")
+	r.git("commit", "--quiet", "-m", "commit")
+	for _, tag := range tags {
+		r.git("tag", "-a", tag, "-m", tag)
+	}
+}
+
+func TestVersionsNested(t *testing.T) {
+	r := newTestRepo(t)
+	r.write("go.mod", "module example.com/root\n\ngo 1.14\n")
+	r.write("root.go", "package root\n\nfunc Root() {}\n")
+	r.write("sub/go.mod", "module example.com/root/sub\n\ngo 1.14\n")
+	r.write("sub/sub.go", "package sub\n\nfunc A() {}\n")
+	r.commit("v1.0.0", "sub/v0.1.0")
+	r.write("sub/sub.go", "package sub\n\nfunc A() {}\n\nfunc B() {}\n")
+	r.commit("sub/v0.2.0")
+	r.write("root.go", "package root\n\nfunc Root() {}\n\nfunc Root2() {}\n")
+	r.commit("v1.1.0", "other/v2.0.0")
+
+	ctx := context.Background()
+	subdir := filepath.Join(r.dir, "sub")
+
+	tests := []struct {
+		dir  string
+		want []string
+	}{
+		{r.dir, []string{"v1.0.0", "v1.1.0"}},
+		{subdir, []string{"v0.1.0", "v0.2.0"}},
+	}
+	for _, tt := range tests {
+		got, err := Versions(ctx, tt.dir)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Versions(%s) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+
+	// versions of the nested module refer to its prefixed tags
+	module, err := ParseRevision(ctx, subdir, "v0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := module.Packages["example.com/root/sub"]
+	if _, found := pkg[".A"]; !found || len(pkg) != 1 {
+		t.Errorf("ParseRevision(sub, v0.1.0) = %v, want only A", pkg)
+	}
+
+	latest, err := latestVersion(ctx, subdir, makeOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	} else if latest != "v0.2.0" {
+		t.Errorf("latestVersion(sub) = %s, want v0.2.0", latest)
+	}
+}
+
+func TestTagNested(t *testing.T) {
+	r := newTestRepo(t)
+	r.git("checkout", "--quiet", "-b", "main")
+	r.write("go.mod", "module example.com/root\n\ngo 1.14\n")
+	r.write("sub/go.mod", "module example.com/root/sub\n\ngo 1.14\n")
+	r.write("sub/sub.go", "package sub\n\nfunc A() {}\n")
+	r.commit("sub/v0.1.0")
+	r.write("sub/sub.go", "package sub\n\nfunc A() {}\n\nfunc B() {}\n")
+	r.commit("v1.0.0")
+
+	var plan []string
+	version, err := Tag(context.Background(), filepath.Join(r.dir, "sub"), WithDryRun(),
+		WithPlan(func(cmdline string) { plan = append(plan, cmdline) }))
+	if err != nil {
+		t.Fatal(err)
+	} else if version != "v0.1.1" {
+		t.Errorf("Tag() = %s, want v0.1.1", version)
+	}
+	if len(plan) != 1 || !strings.Contains(plan[0], " tag -a sub/v0.1.1 ") {
+		t.Errorf("Tag() plan = %q, want to tag sub/v0.1.1", plan)
+	}
+}
+
+func TestTagRefusals(t *testing.T) {
+	r := newTestRepo(t)
+	r.git("checkout", "--quiet", "-b", "master")
+	r.write("go.mod", "module example.com/m\n\ngo 1.14\n")
+	r.write("m.go", "package m\n\nfunc A() {}\n")
+	r.commit("v1.0.1")
+	r.write("m.go", "package m\n\nfunc B() {}\n")
+	r.commit()
+
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default branch", nil, "example.com/m@v2.0.0: invalid version"},
+		{"other branch", []Option{WithBranch("main")}, "HEAD is on master, expected branch main"},
+	}
+	for _, tt := range tests {
+		_, err := Tag(ctx, r.dir, append(tt.opts, WithDryRun())...)
+		if refusal, ok := err.(*RefusalError); !ok || !strings.Contains(refusal.Reason, tt.want) {
+			t.Errorf("%s: Tag() error = %v, want refusal %q", tt.name, err, tt.want)
+		}
+	}
+
+	// a module path with the major version suffix may be tagged with the new major version
+	r.write("go.mod", "module example.com/m/v2\n\ngo 1.14\n")
+	r.commit()
+	if version, err := Tag(ctx, r.dir, WithDryRun()); err != nil || version != "v2.0.0" {
+		t.Errorf("Tag() = %s, %v, want v2.0.0", version, err)
+	}
+}
+
+func TestSuggestMaintenanceBranch(t *testing.T) {
+	r := newTestRepo(t)
+	r.git("checkout", "--quiet", "-b", "main")
+	r.write("go.mod", "module example.com/m\n\ngo 1.14\n")
+	r.write("m.go", "package m\n\nfunc A() {}\n")
+	r.commit("v1.0.0")
+	r.write("m.go", "package m\n\nfunc A() {}\n\nfunc B() {}\n")
+	r.commit("v1.1.0")
+
+	// versions tagged on main are not merged into the maintenance branch
+	r.git("checkout", "--quiet", "-b", "release-1.0", "v1.0.0")
+	r.write("m.go", "package m\n\n// A does nothing.\nfunc A() {}\n")
+	r.commit()
+
+	ctx := context.Background()
+	if version, err := Suggest(ctx, r.dir); err != nil || version != "v1.0.1" {
+		t.Errorf("Suggest() = %s, %v, want v1.0.1", version, err)
+	}
+	if version, err := Tag(ctx, r.dir, WithBranch("release-1.0"), WithDryRun()); err != nil ||
+		version != "v1.0.1" {
+		t.Errorf("Tag() = %s, %v, want v1.0.1", version, err)
+	}
+}
@@ -0,0 +1,49 @@
+package modface
+
+import "strings"
+
+const deprecatedPrefix = "Deprecated: "
+
+// deprecation returns the deprecation notice in a doc comment, or an empty string if there is none.
+// Following Go convention, a deprecation notice is a paragraph beginning with "Deprecated: ".
+func deprecation(doc string) string {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if strings.HasPrefix(paragraph, deprecatedPrefix) {
+			return paragraph
+		}
+	}
+	return ""
+}
+
+// RemovedWithoutDeprecation returns the exports removed in a module difference which are not
+// deprecated in any of the given previous versions of the module.
+// Exports of removed packages are included. The result is keyed by package path.
+func RemovedWithoutDeprecation(md *ModuleDifference, previous []*Module) map[string][]Export {
+	wasDeprecated := func(pkgname, id string) bool {
+		for _, module := range previous {
+			if face, found := module.Packages[pkgname][id]; found && face.Deprecated() {
+				return true
+			}
+		}
+		return false
+	}
+
+	undeprecated := make(map[string][]Export)
+	check := func(pkgname string, removals map[string]Export) {
+		for id, face := range removals {
+			if !wasDeprecated(pkgname, id) {
+				undeprecated[pkgname] = append(undeprecated[pkgname], face)
+			}
+		}
+	}
+
+	for pkgname, pkgface := range md.PackageRemovals {
+		check(pkgname, pkgface)
+	}
+	for pkgname, packdiff := range md.PackageChanges {
+		check(pkgname, packdiff.Removals)
+	}
+
+	return undeprecated
+}
@@ -0,0 +1,64 @@
+package modface
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeprecation(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{"", ""},
+		{"Foo does things.\n", ""},
+		{"Deprecated: use Bar.\n", "Deprecated: use Bar."},
+		{"Foo does things.\n\nDeprecated: use Bar.\n", "Deprecated: use Bar."},
+		{"Foo does things.\n\n  Deprecated: use Bar.  \n\nMore.\n", "Deprecated: use Bar."},
+		{"Foo is not Deprecated: at all.\n", ""},
+		{"Foo does things.\nDeprecated: on a continued line.\n", ""},
+		{"deprecated: lower case.\n", ""},
+	}
+
+	for _, tt := range tests {
+		if got := deprecation(tt.doc); got != tt.want {
+			t.Errorf("deprecation(%q) = %q, want %q", tt.doc, got, tt.want)
+		}
+	}
+}
+
+func TestRemovedWithoutDeprecation(t *testing.T) {
+	foo := FuncSignature{Name: "Foo"}
+	fooDeprecated := FuncSignature{Name: "Foo", Deprecation: "Deprecated: use Baz."}
+	bar := FuncSignature{Name: "Bar"}
+	baz := FuncSignature{Name: "Baz"}
+
+	v100 := &Module{Path: "example.com/m", Packages: map[string]PackageInterface{
+		"example.com/m":     {foo.ID(): foo, bar.ID(): bar, baz.ID(): baz},
+		"example.com/m/pkg": {bar.ID(): bar},
+	}}
+	v110 := &Module{Path: "example.com/m", Packages: map[string]PackageInterface{
+		"example.com/m":     {fooDeprecated.ID(): fooDeprecated, bar.ID(): bar, baz.ID(): baz},
+		"example.com/m/pkg": {bar.ID(): bar},
+	}}
+	v200 := &Module{Path: "example.com/m", Packages: map[string]PackageInterface{
+		"example.com/m": {baz.ID(): baz},
+	}}
+
+	want := map[string][]Export{
+		"example.com/m":     {bar},
+		"example.com/m/pkg": {bar},
+	}
+
+	got := RemovedWithoutDeprecation(Diff(v110, v200), []*Module{v100, v110})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RemovedWithoutDeprecation() = %v, want %v", got, want)
+	}
+
+	// without the version which deprecated Foo, its removal is undeprecated
+	got = RemovedWithoutDeprecation(Diff(v110, v200), []*Module{v100})
+	if len(got["example.com/m"]) != 2 {
+		t.Errorf("RemovedWithoutDeprecation() without deprecating version = %v, want Foo and Bar",
+			got["example.com/m"])
+	}
+}
@@ -0,0 +1,50 @@
+package modface
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion identifies the JSON encoding of modules and the information gathered when
+// parsing them. It is incremented whenever either changes, so that stored modules can be
+// recognized as stale.
+const SchemaVersion = 1
+
+// exportJSON is the JSON encoding of an export, tagged with the kind of export.
+type exportJSON struct {
+	Kind string         `json:"kind"`
+	Func *FuncSignature `json:"func,omitempty"`
+}
+
+// MarshalJSON encodes the exports of a package by ID.
+func (pi PackageInterface) MarshalJSON() ([]byte, error) {
+	encoded := make(map[string]exportJSON, len(pi))
+	for id, face := range pi {
+		switch v := face.(type) {
+		case FuncSignature:
+			encoded[id] = exportJSON{Kind: "func", Func: &v}
+		default:
+			return nil, fmt.Errorf("cannot encode export %s of type %T", id, face)
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes the exports of a package encoded by MarshalJSON.
+func (pi *PackageInterface) UnmarshalJSON(data []byte) error {
+	encoded := make(map[string]exportJSON)
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	*pi = make(PackageInterface, len(encoded))
+	for id, v := range encoded {
+		switch {
+		case v.Kind == "func" && v.Func != nil:
+			(*pi)[id] = *v.Func
+		default:
+			return fmt.Errorf("cannot decode export %s of kind %q", id, v.Kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package modface
+
+import (
+	"encoding/json"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestPackageInterfaceJSON(t *testing.T) {
+	foo := FuncSignature{
+		Name:        "Foo",
+		Params:      TypeList{{Name: "int"}, {Name: "T", IsPointer: true}},
+		Results:     TypeList{{Name: "error"}},
+		Doc:         "Foo does something.",
+		Deprecation: "use Bar instead.",
+		Pos:         token.Position{Filename: "pkg/foo.go", Line: 12, Column: 1},
+	}
+	method := FuncSignature{
+		Name:     "Len",
+		Receiver: Type{Name: "T", IsPointer: true},
+		Results:  TypeList{{Name: "int"}},
+	}
+
+	tests := []PackageInterface{
+		{},
+		{foo.ID(): foo},
+		{foo.ID(): foo, method.ID(): method},
+	}
+	for _, pi := range tests {
+		data, err := json.Marshal(pi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got PackageInterface
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, pi) {
+			t.Errorf("decoded %s as %#v, want %#v", data, got, pi)
+		}
+	}
+}
+
+func TestModuleJSON(t *testing.T) {
+	foo := FuncSignature{Name: "Foo", Params: TypeList{{Name: "string"}}}
+	module := &Module{
+		Path: "example.com/m",
+		Packages: map[string]PackageInterface{
+			"example.com/m":       {foo.ID(): foo},
+			"example.com/m/x/exp": {},
+		},
+		Stability: map[string]Stability{"example.com/m/x/exp": Unstable},
+	}
+
+	data, err := json.Marshal(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got *Module
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, module) {
+		t.Errorf("decoded %s as %#v, want %#v", data, got, module)
+	}
+}
+
+func TestPackageInterfaceUnmarshalInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{".Foo": {"kind": "type"}}`,
+		`{".Foo": {"kind": "func"}}`,
+		`[]`,
+	} {
+		var pi PackageInterface
+		if err := json.Unmarshal([]byte(data), &pi); err == nil {
+			t.Errorf("decoded %s without error, want error", data)
+		}
+	}
+}
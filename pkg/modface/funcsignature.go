@@ -3,6 +3,7 @@ package modface
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	"strings"
 )
 
@@ -11,11 +12,15 @@ import (
 // The Signature is a complete representation of the function's interface
 // and should be directly comparable between different commits to ensure
 // that backwards compatibility is maintained.
+// The Doc, Deprecation, and Pos are informational and do not affect comparison.
 type FuncSignature struct {
-	Name     string
-	Receiver Type
-	Params   TypeList
-	Results  TypeList
+	Name        string
+	Receiver    Type
+	Params      TypeList
+	Results     TypeList
+	Doc         string
+	Deprecation string
+	Pos         token.Position
 }
 
 // ID returns a unique identifier for the function signature.
@@ -47,6 +52,21 @@ func (fs FuncSignature) String() string {
 	return sb.String()
 }
 
+// Documentation returns the doc comment of the function.
+func (fs FuncSignature) Documentation() string {
+	return fs.Doc
+}
+
+// Deprecated returns true if the function is marked as deprecated, otherwise false.
+func (fs FuncSignature) Deprecated() bool {
+	return fs.Deprecation != ""
+}
+
+// Position returns the position of the function declaration.
+func (fs FuncSignature) Position() token.Position {
+	return fs.Pos
+}
+
 func (fs FuncSignature) compareString() string {
 	// TODO: consider underlying package change. For example:
 	// before: func(x pkg.Type) depends on module named github.com/a/pkg
@@ -62,6 +82,11 @@ func ParseFuncSignature(decl *ast.FuncDecl) FuncSignature {
 		Results: extractTypeList(decl.Type.Results),
 	}
 
+	if decl.Doc != nil {
+		fs.Doc = decl.Doc.Text()
+		fs.Deprecation = deprecation(fs.Doc)
+	}
+
 	recvlist := extractTypeList(decl.Recv)
 	if len(recvlist) > 0 {
 		fs.Receiver = recvlist[0]
@@ -0,0 +1,70 @@
+package modface
+
+import "strings"
+
+// VersionedModule is a module interface at a particular version.
+type VersionedModule struct {
+	Version string
+	Module  *Module
+}
+
+// ExportHistory records the versions in which an export first appeared and last changed.
+// An export that is removed and later restored is considered added when it is restored.
+type ExportHistory struct {
+	Export      Export
+	Added       string
+	LastChanged string
+}
+
+// Since replays the history of a module and returns the history of each export present in the
+// final version. The history must be ordered from oldest to newest version.
+// Packages are matched across versions by their path within the module, so that history is kept
+// when the module path changes, such as for a new major version suffix.
+// The result is keyed by package path in the final version, then by export ID.
+func Since(history []VersionedModule) map[string]map[string]ExportHistory {
+	annotations := make(map[string]map[string]ExportHistory)
+	pkgpaths := make(map[string]string)
+
+	for _, vm := range history {
+		next := make(map[string]map[string]ExportHistory)
+		pkgpaths = make(map[string]string)
+		for pkgname, pkgface := range vm.Module.Packages {
+			relpath := relativePackagePath(vm.Module.Path, pkgname)
+			pkgpaths[relpath] = pkgname
+			prevpack := annotations[relpath]
+			nextpack := make(map[string]ExportHistory)
+			for id, face := range pkgface {
+				prev, found := prevpack[id]
+				switch {
+				case !found:
+					// export is new in this version
+					nextpack[id] = ExportHistory{Export: face, Added: vm.Version, LastChanged: vm.Version}
+				case !ExportsEqual(prev.Export, face):
+					// export signature changed in this version
+					nextpack[id] = ExportHistory{Export: face, Added: prev.Added, LastChanged: vm.Version}
+				default:
+					nextpack[id] = ExportHistory{Export: face, Added: prev.Added, LastChanged: prev.LastChanged}
+				}
+			}
+			next[relpath] = nextpack
+		}
+		annotations = next
+	}
+
+	result := make(map[string]map[string]ExportHistory, len(annotations))
+	for relpath, pkghistory := range annotations {
+		result[pkgpaths[relpath]] = pkghistory
+	}
+	return result
+}
+
+// relativePackagePath returns the path of a package within its module.
+// The module's root package has an empty relative path.
+func relativePackagePath(modpath, pkgname string) string {
+	if pkgname == modpath {
+		return ""
+	} else if strings.HasPrefix(pkgname, modpath+"/") {
+		return pkgname[len(modpath)+1:]
+	}
+	return pkgname
+}
@@ -0,0 +1,62 @@
+package modface
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSince(t *testing.T) {
+	foo := FuncSignature{Name: "Foo", Params: TypeList{{Name: "int"}}}
+	fooChanged := FuncSignature{Name: "Foo", Params: TypeList{{Name: "int"}, {Name: "int"}}}
+	bar := FuncSignature{Name: "Bar"}
+	baz := FuncSignature{Name: "Baz"}
+
+	history := []VersionedModule{
+		{"v1.0.0", &Module{Path: "example.com/m", Packages: map[string]PackageInterface{
+			"example.com/m":     {foo.ID(): foo},
+			"example.com/m/pkg": {bar.ID(): bar},
+		}}},
+		{"v1.1.0", &Module{Path: "example.com/m", Packages: map[string]PackageInterface{
+			"example.com/m":     {foo.ID(): foo, baz.ID(): baz},
+			"example.com/m/pkg": {bar.ID(): bar},
+		}}},
+		{"v2.0.0", &Module{Path: "example.com/m/v2", Packages: map[string]PackageInterface{
+			"example.com/m/v2":     {fooChanged.ID(): fooChanged, baz.ID(): baz},
+			"example.com/m/v2/pkg": {bar.ID(): bar},
+		}}},
+	}
+
+	want := map[string]map[string]ExportHistory{
+		"example.com/m/v2": {
+			fooChanged.ID(): {Export: fooChanged, Added: "v1.0.0", LastChanged: "v2.0.0"},
+			baz.ID():        {Export: baz, Added: "v1.1.0", LastChanged: "v1.1.0"},
+		},
+		"example.com/m/v2/pkg": {
+			bar.ID(): {Export: bar, Added: "v1.0.0", LastChanged: "v1.0.0"},
+		},
+	}
+
+	if got := Since(history); !reflect.DeepEqual(got, want) {
+		t.Errorf("Since() = %v, want %v", got, want)
+	}
+}
+
+func TestSinceRestored(t *testing.T) {
+	foo := FuncSignature{Name: "Foo"}
+	history := []VersionedModule{
+		{"v1.0.0", &Module{Path: "example.com/m", Packages: map[string]PackageInterface{
+			"example.com/m": {foo.ID(): foo},
+		}}},
+		{"v1.1.0", &Module{Path: "example.com/m", Packages: map[string]PackageInterface{
+			"example.com/m": {},
+		}}},
+		{"v1.2.0", &Module{Path: "example.com/m", Packages: map[string]PackageInterface{
+			"example.com/m": {foo.ID(): foo},
+		}}},
+	}
+
+	got := Since(history)["example.com/m"][foo.ID()]
+	if got.Added != "v1.2.0" || got.LastChanged != "v1.2.0" {
+		t.Errorf("restored export history = %+v, want added and last changed in v1.2.0", got)
+	}
+}
@@ -8,6 +8,11 @@ type ModuleDifference struct {
 	PackageRemovals  map[string]PackageInterface
 	PackageAdditions map[string]PackageInterface
 	PackageChanges   map[string]*PackageDifference
+	// Allowed contains the reasons that package removals have been allowed, keyed by package.
+	Allowed map[string]string
+	// Unstable contains the packages which are unstable in the old version of the module, or in
+	// the new version for added packages. Differences in unstable packages are not breaking.
+	Unstable map[string]bool
 }
 
 func newModuleDifference() *ModuleDifference {
@@ -15,6 +20,8 @@ func newModuleDifference() *ModuleDifference {
 	md.PackageRemovals = make(map[string]PackageInterface)
 	md.PackageAdditions = make(map[string]PackageInterface)
 	md.PackageChanges = make(map[string]*PackageDifference)
+	md.Allowed = make(map[string]string)
+	md.Unstable = make(map[string]bool)
 	return md
 }
 
@@ -29,15 +36,59 @@ func (md ModuleDifference) Any() bool {
 
 // Breaking returns true if there are any breaking differences, otherwise false.
 // Any package removals or packages with breaking changes are considered breaking changes
-// for the module.
+// for the module, unless they have been allowed or the packages are unstable.
 func (md ModuleDifference) Breaking() bool {
-	if !md.ModPathsMatch || len(md.PackageRemovals) > 0 {
+	if !md.ModPathsMatch {
+		return true
+	}
+	for pkgname := range md.PackageRemovals {
+		if _, allowed := md.Allowed[pkgname]; !allowed && !md.Unstable[pkgname] {
+			return true
+		}
+	}
+	for pkgname, packdiff := range md.PackageChanges {
+		if packdiff.Breaking() && !md.Unstable[pkgname] {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyAllowed returns true if any breaking differences have been allowed, otherwise false.
+func (md ModuleDifference) AnyAllowed() bool {
+	if len(md.Allowed) > 0 {
 		return true
 	}
 	for _, packdiff := range md.PackageChanges {
-		if packdiff.Breaking() {
+		if packdiff.AnyAllowed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow marks a breaking difference as intentional so that it is not considered breaking.
+// If id is empty, the removal of the package is allowed; otherwise, the removal or change of the
+// export with that ID in the package is allowed.
+// Allow returns true if a matching breaking difference was found, otherwise false.
+func (md *ModuleDifference) Allow(pkgname, id, reason string) bool {
+	if id == "" {
+		if _, found := md.PackageRemovals[pkgname]; found {
+			md.Allowed[pkgname] = reason
 			return true
 		}
+		return false
+	}
+
+	packdiff, found := md.PackageChanges[pkgname]
+	if !found {
+		return false
+	}
+	_, removed := packdiff.Removals[id]
+	_, changed := packdiff.Changes[id]
+	if removed || changed {
+		packdiff.Allowed[id] = reason
+		return true
 	}
 	return false
 }
@@ -50,6 +101,10 @@ func Diff(oldmod, newmod *Module) *ModuleDifference {
 	moddiff.ModPathsMatch = oldmod.Path == newmod.Path
 
 	for pkgname, oldpack := range oldmod.Packages {
+		if oldmod.PackageStability(pkgname) == Unstable {
+			moddiff.Unstable[pkgname] = true
+		}
+
 		newpack, found := newmod.Packages[pkgname]
 		if !found {
 			// package in old but not in new, so it has been removed
@@ -67,6 +122,9 @@ func Diff(oldmod, newmod *Module) *ModuleDifference {
 		if !found {
 			// package in new but not in old, so it has been added
 			moddiff.PackageAdditions[pkgname] = newface
+			if newmod.PackageStability(pkgname) == Unstable {
+				moddiff.Unstable[pkgname] = true
+			}
 		}
 	}
 
@@ -1,15 +1,16 @@
 package modface
 
 import (
+	"context"
+	"go/token"
 	"io/ioutil"
 	"path/filepath"
-	"strings"
-
-	"os/exec"
+	"runtime"
+	"sync"
 
 	"github.com/dgravesa/gover/pkg/modparse"
+	"github.com/dgravesa/gover/pkg/versions"
 	"golang.org/x/mod/modfile"
-	"golang.org/x/mod/semver"
 )
 
 // ModuleInterface represents all exports of a module.
@@ -17,9 +18,16 @@ type ModuleInterface map[string]PackageInterface
 
 // Module represents a module.
 // The Package member contains all exports of the module.
+// The Stability member contains the stability of each package; packages not present are stable.
 type Module struct {
-	Path     string
-	Packages map[string]PackageInterface
+	Path      string
+	Packages  map[string]PackageInterface
+	Stability map[string]Stability
+}
+
+// PackageStability returns the stability level of a package in the module.
+func (m Module) PackageStability(pkgname string) Stability {
+	return m.Stability[pkgname]
 }
 
 // Export represents an export.
@@ -31,6 +39,16 @@ type Export interface {
 	// An export's ID must be unique within its package.
 	ID() string
 
+	// Documentation returns the doc comment of an export.
+	Documentation() string
+
+	// Deprecated returns true if the export is marked as deprecated.
+	Deprecated() bool
+
+	// Position returns the position of the export's declaration.
+	// The filename is relative to the module root.
+	Position() token.Position
+
 	// compareString returns a complete string representation of the export such that any two
 	// exports with matching compareStrings may be considered equal, and any two exports with
 	// differing compareStrings may be considered not equal.
@@ -43,7 +61,20 @@ func ExportsEqual(a, b Export) bool {
 }
 
 // ParseModule parses a module and returns all of its export signatures.
+// Packages are parsed concurrently by up to GOMAXPROCS workers.
 func ParseModule(moddir string) (*Module, error) {
+	return ParseModuleJobs(moddir, 0)
+}
+
+// ParseModuleJobs parses a module like ParseModule, with packages parsed concurrently by up to
+// jobs workers. If jobs is less than 1, GOMAXPROCS workers are used.
+func ParseModuleJobs(moddir string, jobs int) (*Module, error) {
+	return ParseModuleContext(context.Background(), moddir, jobs)
+}
+
+// ParseModuleContext parses a module like ParseModuleJobs, but stops parsing packages and returns
+// the context's error if ctx is done before the module is parsed.
+func ParseModuleContext(ctx context.Context, moddir string, jobs int) (*Module, error) {
 	dirs, err := modparse.ModuleDirs(moddir)
 	if err != nil {
 		return nil, err
@@ -58,30 +89,61 @@ func ParseModule(moddir string) (*Module, error) {
 	module := new(Module)
 	module.Path = modfile.ModulePath(mfile)
 	module.Packages = make(ModuleInterface)
+	module.Stability = make(map[string]Stability)
 
-	for _, dir := range dirs {
-		parseDir(module.Packages, moddir, dir, module.Path)
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+	if jobs > len(dirs) {
+		jobs = len(dirs)
 	}
 
-	return module, nil
-}
-
-// Versions returns all the versions for a module pointed to by moddir.
-func Versions(moddir string) ([]string, error) {
-	tagcmd := exec.Command("git", "-C", moddir, "tag")
-	tagout, err := tagcmd.Output()
-	if err != nil {
+	// parse directories in a worker pool, with each result stored at the index of its directory
+	// so that results are merged in the order of dirs regardless of when they complete
+	fset := token.NewFileSet() // safe for concurrent use by the parser
+	results := make([]*dirInterface, len(dirs))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if ctx.Err() != nil {
+					continue
+				}
+				results[i], _ = parseDir(fset, module.Path, moddir, dirs[i])
+			}
+		}()
+	}
+	for i := range dirs {
+		if ctx.Err() != nil {
+			break
+		}
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	tags := strings.Split(string(tagout), "\n")
-
-	versions := []string{}
-	for _, tag := range tags {
-		if semver.IsValid(tag) {
-			versions = append(versions, tag)
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		module.Packages[result.Path] = result.Exports
+		if result.Stability != Stable {
+			module.Stability[result.Path] = result.Stability
 		}
 	}
 
-	return versions, nil
+	return module, nil
+}
+
+// Versions returns all the versions for a module pointed to by moddir in ascending order.
+//
+// Deprecated: use the versions package, which also supports nested modules and module proxies.
+func Versions(moddir string) ([]string, error) {
+	return versions.Git(context.Background(), moddir, versions.Options{})
 }
@@ -0,0 +1,101 @@
+package modface
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeSyntheticModule writes a module with the given number of packages, files per package,
+// and functions per file into a temporary directory, and returns the directory.
+func writeSyntheticModule(tb testing.TB, packages, files, funcs int) string {
+	tb.Helper()
+	dir, err := ioutil.TempDir("", "modface-synthetic-*")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	write := func(name, content string) {
+		filename := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	write("go.mod", "module example.com/synthetic\n\ngo 1.14\n")
+	for p := 0; p < packages; p++ {
+		pkgname := fmt.Sprintf("pkg%03d", p)
+		if p%10 == 0 {
+			pkgname = filepath.Join("x", pkgname)
+		}
+		for f := 0; f < files; f++ {
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "package %s\n\n", filepath.Base(pkgname))
+			fmt.Fprintf(&sb, "// T%d is a type.\ntype T%d struct{}\n\n", f, f)
+			for n := 0; n < funcs; n++ {
+				fmt.Fprintf(&sb, "// F%d_%d does something.\nfunc F%d_%d(a int, b *T%d) (string, error) {\n\treturn \"\", nil\n}\n\n", f, n, f, n, f)
+				fmt.Fprintf(&sb, "// M%d does something.\nfunc (t *T%d) M%d(s []string) int {\n\treturn len(s)\n}\n\n", n, f, n)
+			}
+			write(filepath.Join(pkgname, fmt.Sprintf("file%02d.go", f)), sb.String())
+		}
+	}
+
+	return dir
+}
+
+func TestParseModuleJobsDeterministic(t *testing.T) {
+	dir := writeSyntheticModule(t, 40, 3, 5)
+	defer os.RemoveAll(dir)
+
+	sequential, err := ParseModuleJobs(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(sequential.Packages); n != 40 {
+		t.Fatalf("parsed %d packages, want 40", n)
+	}
+
+	for _, jobs := range []int{2, 8, 100} {
+		parallel, err := ParseModuleJobs(dir, jobs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sequential, parallel) {
+			t.Errorf("module parsed with %d jobs differs from module parsed with 1 job", jobs)
+		}
+	}
+}
+
+func BenchmarkParseModule(b *testing.B) {
+	dir := writeSyntheticModule(b, 300, 5, 10)
+	defer os.RemoveAll(dir)
+
+	for _, jobs := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := ParseModuleJobs(dir, jobs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseModuleContextCanceled(t *testing.T) {
+	dir := writeSyntheticModule(t, 20, 1, 1)
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ParseModuleContext(ctx, dir, 4); err != context.Canceled {
+		t.Errorf("ParseModuleContext() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -5,6 +5,10 @@ type PackageDifference struct {
 	Additions map[string]Export
 	Removals  map[string]Export
 	Changes   map[string]ExportDifference
+	// Deprecations contains exports which are deprecated in the new version but not the old.
+	Deprecations map[string]ExportDifference
+	// Allowed contains the reasons that removals or changes have been allowed, keyed by export ID.
+	Allowed map[string]string
 }
 
 func newPackageDifference() *PackageDifference {
@@ -12,6 +16,8 @@ func newPackageDifference() *PackageDifference {
 	pd.Additions = make(map[string]Export)
 	pd.Removals = make(map[string]Export)
 	pd.Changes = make(map[string]ExportDifference)
+	pd.Deprecations = make(map[string]ExportDifference)
+	pd.Allowed = make(map[string]string)
 	return pd
 }
 
@@ -22,22 +28,37 @@ type ExportDifference struct {
 }
 
 // Any returns true if there are any differences, otherwise false.
+// New deprecations are differences, so a version which only deprecates exports adds a feature.
 func (pd PackageDifference) Any() bool {
-	if len(pd.Additions) > 0 || len(pd.Removals) > 0 || len(pd.Changes) > 0 {
+	if len(pd.Additions) > 0 || len(pd.Removals) > 0 || len(pd.Changes) > 0 ||
+		len(pd.Deprecations) > 0 {
 		return true
 	}
 	return false
 }
 
 // Breaking returns true if there are any breaking differences, otherwise false.
-// Any interface removals or changes in signature are considered breaking changes.
+// Any interface removals or changes in signature are considered breaking changes,
+// unless they have been allowed.
 func (pd PackageDifference) Breaking() bool {
-	if len(pd.Removals) > 0 || len(pd.Changes) > 0 {
-		return true
+	for id := range pd.Removals {
+		if _, allowed := pd.Allowed[id]; !allowed {
+			return true
+		}
+	}
+	for id := range pd.Changes {
+		if _, allowed := pd.Allowed[id]; !allowed {
+			return true
+		}
 	}
 	return false
 }
 
+// AnyAllowed returns true if any breaking differences have been allowed, otherwise false.
+func (pd PackageDifference) AnyAllowed() bool {
+	return len(pd.Allowed) > 0
+}
+
 // PackageDiff returns an object representing the difference between two package versions.
 func PackageDiff(oldpack, newpack PackageInterface) *PackageDifference {
 	packdiff := newPackageDifference()
@@ -47,11 +68,20 @@ func PackageDiff(oldpack, newpack PackageInterface) *PackageDifference {
 		if !found {
 			// face in old but not in new, so it has been removed
 			packdiff.Removals[id] = oldface
-		} else if !ExportsEqual(oldface, newface) {
-			// face has changed
-			packdiff.Changes[id] = ExportDifference{
-				Old: oldface,
-				New: newface,
+		} else {
+			if !ExportsEqual(oldface, newface) {
+				// face has changed
+				packdiff.Changes[id] = ExportDifference{
+					Old: oldface,
+					New: newface,
+				}
+			}
+			if newface.Deprecated() && !oldface.Deprecated() {
+				// face has been deprecated
+				packdiff.Deprecations[id] = ExportDifference{
+					Old: oldface,
+					New: newface,
+				}
 			}
 		}
 	}
@@ -11,12 +11,21 @@ import (
 // PackageInterface represents all exports of a package.
 type PackageInterface map[string]Export
 
-func parseDir(inout ModuleInterface, basedir string, pkgdir, modname string) error {
-	fset := token.NewFileSet()
+// dirInterface is the interface of the package parsed from a module directory.
+type dirInterface struct {
+	Path      string
+	Exports   PackageInterface
+	Stability Stability
+}
+
+// parseDir parses the package in a directory of the module rooted at basedir.
+// If the directory does not contain a package with exports, nil is returned.
+// The file set may be shared between concurrent calls.
+func parseDir(fset *token.FileSet, modpath, basedir, pkgdir string) (*dirInterface, error) {
 	dir := filepath.Join(basedir, pkgdir)
-	pkgs, err := parser.ParseDir(fset, dir, nil, 0)
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	hasExports := func(pkg *ast.Package) bool {
@@ -29,13 +38,18 @@ func parseDir(inout ModuleInterface, basedir string, pkgdir, modname string) err
 	}
 
 	// parse packages
+	var result *dirInterface
 	for _, pkg := range pkgs {
 		if hasExports(pkg) {
-			pkgfullpath := filepath.Join(modname, pkgdir)
-			pf, ok := inout[pkgfullpath]
-			if !ok {
-				pf = make(PackageInterface)
-				inout[pkgfullpath] = pf
+			if result == nil {
+				result = &dirInterface{
+					Path:    filepath.ToSlash(filepath.Join(modpath, pkgdir)),
+					Exports: make(PackageInterface),
+				}
+			}
+			pf := result.Exports
+			if dirStability(pkgdir) == Unstable || docStability(pkg) == Unstable {
+				result.Stability = Unstable
 			}
 
 			for _, file := range pkg.Files {
@@ -43,6 +57,7 @@ func parseDir(inout ModuleInterface, basedir string, pkgdir, modname string) err
 					switch v := decl.(type) {
 					case *ast.FuncDecl:
 						fs := ParseFuncSignature(v)
+						fs.Pos = modulePosition(fset, basedir, v.Pos())
 						funcExported := ast.IsExported(fs.Name)
 						recvNotAnonymous := !fs.Receiver.IsDefined() || fs.Receiver.IsExported()
 						if funcExported && recvNotAnonymous {
@@ -54,5 +69,14 @@ func parseDir(inout ModuleInterface, basedir string, pkgdir, modname string) err
 		}
 	}
 
-	return nil
+	return result, nil
+}
+
+// modulePosition returns a position with the filename relative to the module root.
+func modulePosition(fset *token.FileSet, basedir string, pos token.Pos) token.Position {
+	position := fset.Position(pos)
+	if relpath, err := filepath.Rel(basedir, position.Filename); err == nil {
+		position.Filename = filepath.ToSlash(relpath)
+	}
+	return position
 }
@@ -0,0 +1,60 @@
+package modface
+
+import (
+	"go/ast"
+	"path/filepath"
+	"strings"
+)
+
+// Stability is the stability level of a package.
+// Changes to unstable packages are not considered breaking changes for the module.
+type Stability int
+
+const (
+	// Stable packages must maintain backwards compatibility.
+	Stable Stability = iota
+	// Unstable packages may change freely.
+	Unstable
+)
+
+func (s Stability) String() string {
+	if s == Unstable {
+		return "unstable"
+	}
+	return "stable"
+}
+
+// unstableDirs are names of top-level module directories whose packages are considered unstable.
+var unstableDirs = map[string]bool{
+	"x":   true,
+	"exp": true,
+}
+
+// unstableDirective marks a package as unstable when it appears in a package comment.
+const unstableDirective = "gover:unstable"
+
+// dirStability returns the stability implied by the path of a package relative to the module root.
+// Only the top-level directory is considered, so that a package such as codec/x is stable.
+func dirStability(pkgdir string) Stability {
+	toplevel := strings.SplitN(filepath.ToSlash(filepath.Clean(pkgdir)), "/", 2)[0]
+	if unstableDirs[toplevel] {
+		return Unstable
+	}
+	return Stable
+}
+
+// docStability returns the stability declared in the package comments of a package.
+func docStability(pkg *ast.Package) Stability {
+	for _, file := range pkg.Files {
+		if file.Doc == nil {
+			continue
+		}
+		for _, comment := range file.Doc.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			if text == unstableDirective {
+				return Unstable
+			}
+		}
+	}
+	return Stable
+}
@@ -0,0 +1,130 @@
+package modface
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirStability(t *testing.T) {
+	tests := []struct {
+		pkgdir string
+		want   Stability
+	}{
+		{".", Stable},
+		{"foo", Stable},
+		{"x", Unstable},
+		{"x/foo", Unstable},
+		{"exp", Unstable},
+		{"exp/foo/bar", Unstable},
+		{"./exp/foo", Unstable},
+		{"codec/x", Stable},
+		{"foo/exp/bar", Stable},
+		{"xx", Stable},
+	}
+
+	for _, tt := range tests {
+		if got := dirStability(tt.pkgdir); got != tt.want {
+			t.Errorf("dirStability(%q) = %v, want %v", tt.pkgdir, got, tt.want)
+		}
+	}
+}
+
+// parseFiles writes a module with the given files into a temporary directory and parses it.
+func parseFiles(t *testing.T, files map[string]string) *Module {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "modface-stability-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files["go.mod"] = "module example.com/m\n\ngo 1.14\n"
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	module, err := ParseModule(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return module
+}
+
+func TestDocStability(t *testing.T) {
+	module := parseFiles(t, map[string]string{
+		"stable/a.go":   "// Package stable is stable.\npackage stable\n\nfunc A() {}\n",
+		"unstable/a.go": "// Package unstable changes.\n//\n// gover:unstable\npackage unstable\n",
+		"unstable/b.go": "package unstable\n\nfunc B() {}\n",
+		"other/a.go":    "// Package other is not gover:unstable.\npackage other\n\nfunc A() {}\n",
+		"x/exp/a.go":    "package exp\n\nfunc A() {}\n",
+	})
+
+	tests := map[string]Stability{
+		"example.com/m/stable":   Stable,
+		"example.com/m/unstable": Unstable,
+		"example.com/m/other":    Stable,
+		"example.com/m/x/exp":    Unstable,
+	}
+	for pkgname, want := range tests {
+		if got := module.PackageStability(pkgname); got != want {
+			t.Errorf("PackageStability(%s) = %v, want %v", pkgname, got, want)
+		}
+	}
+}
+
+func TestDiffStability(t *testing.T) {
+	const (
+		stable   = "package p\n\nfunc A() {}\n"
+		unstable = "// gover:unstable\npackage p\n\nfunc A() {}\n"
+		changed  = "package p\n\nfunc A(int) {}\n"
+	)
+
+	tests := []struct {
+		name         string
+		old, new     map[string]string
+		wantBreaking bool
+	}{
+		{"stable change", map[string]string{"p/p.go": stable},
+			map[string]string{"p/p.go": changed}, true},
+		{"unstable change", map[string]string{"p/p.go": unstable},
+			map[string]string{"p/p.go": changed}, false},
+		{"unstable removal", map[string]string{"p/p.go": unstable, "q/q.go": "package q\n"},
+			map[string]string{"q/q.go": "package q\n"}, false},
+		{"unstable directory removal",
+			map[string]string{"x/p/p.go": stable, "q/q.go": "package q\n"},
+			map[string]string{"q/q.go": "package q\n"}, false},
+		// stability comes from the old version, so that marking a package unstable does not
+		// excuse the breaking changes made in the same version, and stabilizing it does not
+		// make its final changes breaking
+		{"newly unstable change", map[string]string{"p/p.go": stable},
+			map[string]string{"p/p.go": "// gover:unstable\n" + changed}, true},
+		{"newly stable change", map[string]string{"p/p.go": unstable},
+			map[string]string{"p/p.go": changed}, false},
+	}
+
+	for _, tt := range tests {
+		md := Diff(parseFiles(t, tt.old), parseFiles(t, tt.new))
+		if !md.Any() {
+			t.Errorf("%s: Diff() has no differences", tt.name)
+		} else if got := md.Breaking(); got != tt.wantBreaking {
+			t.Errorf("%s: Breaking() = %v, want %v", tt.name, got, tt.wantBreaking)
+		}
+	}
+}
+
+func TestDiffAddedUnstable(t *testing.T) {
+	md := Diff(parseFiles(t, map[string]string{"p/p.go": "package p\n"}),
+		parseFiles(t, map[string]string{"p/p.go": "package p\n",
+			"q/q.go": "// gover:unstable\npackage q\n\nfunc A() {}\n"}))
+	if !md.Unstable["example.com/m/q"] || md.Unstable["example.com/m/p"] {
+		t.Errorf("Diff() unstable packages = %v, want only the added example.com/m/q", md.Unstable)
+	}
+}
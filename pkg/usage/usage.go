@@ -0,0 +1,280 @@
+// Package usage finds references to the exports of a module in Go source code.
+//
+// References are resolved syntactically, without type checking. Functions are matched through
+// the imports of each file, and by name within their own package. Methods are matched by name
+// against the methods of the packages a file imports, as the type of the receiver is not known.
+package usage
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/modface"
+	"github.com/dgravesa/gover/pkg/modparse"
+	"golang.org/x/mod/modfile"
+)
+
+// Reference is a use of an export in a Go source file.
+type Reference struct {
+	Package string // import path of the package which declares the export
+	ID      string // ID of the export, as returned by modface.Export.ID
+	// Pos is the position of the reference, with the filename relative to the scanned module.
+	Pos token.Position
+	// Inferred is true for methods matched by name only, which may be false positives.
+	Inferred bool
+	// Internal is true for references from within the module that declares the export.
+	Internal bool
+}
+
+// index holds the exports of a package by name.
+type index struct {
+	funcs   map[string]string   // export ID by function name
+	methods map[string][]string // export IDs by method name
+}
+
+func newIndex(pkgface modface.PackageInterface) *index {
+	idx := &index{funcs: make(map[string]string), methods: make(map[string][]string)}
+	for id, face := range pkgface {
+		fs, ok := face.(modface.FuncSignature)
+		if !ok {
+			continue
+		} else if fs.Receiver.IsDefined() {
+			idx.methods[fs.Name] = append(idx.methods[fs.Name], id)
+		} else {
+			idx.funcs[fs.Name] = id
+		}
+	}
+	for _, ids := range idx.methods {
+		sort.Strings(ids)
+	}
+	return idx
+}
+
+// Scan returns the references to exports of target in the Go files of the module at dir,
+// including test files, sorted by position. Directories and files ignored by the go command, such
+// as testdata, are not scanned, and files which cannot be parsed are skipped.
+// Scanning stops with the error of ctx if it is done before all directories are scanned.
+func Scan(ctx context.Context, dir string, target *modface.Module) ([]Reference, error) {
+	gomod, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	modpath := modfile.ModulePath(gomod)
+
+	dirs, err := modparse.ModuleDirs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make(map[string]*index)
+	for pkgname, pkgface := range target.Packages {
+		indices[pkgname] = newIndex(pkgface)
+	}
+
+	refs := []Reference{}
+	fset := token.NewFileSet()
+	for _, pkgdir := range dirs {
+		if ignoredDir(pkgdir) {
+			continue
+		} else if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		entries, err := ioutil.ReadDir(filepath.Join(dir, pkgdir))
+		if err != nil {
+			return nil, err
+		}
+		self := path.Join(modpath, filepath.ToSlash(pkgdir))
+		for _, entry := range entries {
+			if entry.IsDir() || !isGoFile(entry.Name()) {
+				continue
+			}
+			filename := filepath.Join(dir, pkgdir, entry.Name())
+			file, err := parser.ParseFile(fset, filename, nil, 0)
+			if err != nil {
+				// a file which does not parse cannot be built, so it cannot refer to anything
+				continue
+			}
+
+			s := &fileScanner{
+				fset:     fset,
+				indices:  indices,
+				internal: modpath == target.Path,
+				imports:  make(map[string]string),
+			}
+			if relpath, err := filepath.Rel(dir, filename); err == nil {
+				s.filename = filepath.ToSlash(relpath)
+			}
+			if !strings.HasSuffix(file.Name.Name, "_test") && indices[self] != nil {
+				s.unqualified = append(s.unqualified, self)
+			}
+			s.scan(file)
+			refs = append(refs, s.refs...)
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		a, b := refs[i].Pos, refs[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		} else if a.Offset != b.Offset {
+			return a.Offset < b.Offset
+		}
+		return refs[i].ID < refs[j].ID
+	})
+	return refs, nil
+}
+
+// ignoredDir reports whether the go command ignores a directory of a module, because it is named
+// testdata or starts with an underscore.
+func ignoredDir(pkgdir string) bool {
+	for _, elem := range strings.Split(filepath.ToSlash(pkgdir), "/") {
+		if elem == "testdata" || strings.HasPrefix(elem, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// isGoFile reports whether a file is a Go source file that is not ignored by the go command.
+func isGoFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasPrefix(name, ".") && !strings.HasPrefix(name, "_")
+}
+
+// fileScanner finds the references in a single file.
+type fileScanner struct {
+	fset     *token.FileSet
+	filename string
+	indices  map[string]*index
+	internal bool
+	// imports maps the names that imported packages of the target are referred to by.
+	imports map[string]string
+	// unqualified lists the packages whose functions are referred to without a package name,
+	// which are the package of the file itself and any dot imports.
+	unqualified []string
+	refs        []Reference
+}
+
+func (s *fileScanner) add(pkgname, id string, ident *ast.Ident, inferred bool) {
+	pos := s.fset.Position(ident.Pos())
+	pos.Filename = s.filename
+	s.refs = append(s.refs, Reference{
+		Package:  pkgname,
+		ID:       id,
+		Pos:      pos,
+		Inferred: inferred,
+		Internal: s.internal,
+	})
+}
+
+func (s *fileScanner) scan(file *ast.File) {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || s.indices[importPath] == nil {
+			continue
+		}
+		switch {
+		case spec.Name == nil:
+			s.imports[packageName(importPath)] = importPath
+		case spec.Name.Name == ".":
+			s.unqualified = append(s.unqualified, importPath)
+		case spec.Name.Name != "_":
+			s.imports[spec.Name.Name] = importPath
+		}
+	}
+
+	// packages whose methods may be called in this file
+	methodPackages := append([]string{}, s.unqualified...)
+	for _, importPath := range s.imports {
+		methodPackages = append(methodPackages, importPath)
+	}
+	sort.Strings(methodPackages)
+
+	inspect := s.inspect(methodPackages)
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch v := node.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.FuncDecl:
+			// the declared name is not a reference, but the rest of the declaration may contain some
+			ast.Inspect(v.Type, inspect)
+			if v.Body != nil {
+				ast.Inspect(v.Body, inspect)
+			}
+			return false
+		}
+		return inspect(node)
+	})
+}
+
+// inspect returns the function which records the references at each node.
+func (s *fileScanner) inspect(methodPackages []string) func(ast.Node) bool {
+	var fn func(ast.Node) bool
+	fn = func(node ast.Node) bool {
+		switch v := node.(type) {
+		case *ast.SelectorExpr:
+			// qualified function, if the selector is an imported package that is not shadowed
+			if x, ok := v.X.(*ast.Ident); ok && x.Obj == nil {
+				if importPath, found := s.imports[x.Name]; found {
+					if id, found := s.indices[importPath].funcs[v.Sel.Name]; found {
+						s.add(importPath, id, v.Sel, false)
+					}
+					return false
+				}
+			}
+			// otherwise a method or field of a value of unknown type
+			for _, importPath := range methodPackages {
+				for _, id := range s.indices[importPath].methods[v.Sel.Name] {
+					s.add(importPath, id, v.Sel, true)
+				}
+			}
+			ast.Inspect(v.X, fn)
+			return false
+		case *ast.Ident:
+			// unqualified function, if not resolved to a local declaration
+			if v.Obj != nil && v.Obj.Kind != ast.Fun {
+				return false
+			}
+			for _, importPath := range s.unqualified {
+				if id, found := s.indices[importPath].funcs[v.Name]; found {
+					s.add(importPath, id, v, false)
+				}
+			}
+		case *ast.KeyValueExpr:
+			// struct field names in composite literals are not references
+			if _, ok := v.Key.(*ast.Ident); ok {
+				ast.Inspect(v.Value, fn)
+				return false
+			}
+		}
+		return true
+	}
+	return fn
+}
+
+// packageName guesses the name of an imported package from its import path, skipping any major
+// version suffix.
+func packageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && isDigits(name[1:]) {
+		name = elems[len(elems)-2]
+	}
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
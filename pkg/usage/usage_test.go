@@ -0,0 +1,311 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dgravesa/gover/pkg/modface"
+)
+
+// testTarget returns a target module with a root package, a foo package, and a v2 module path.
+func testTarget(modpath string) *modface.Module {
+	fn := func(name string) modface.FuncSignature {
+		return modface.FuncSignature{Name: name}
+	}
+	method := func(recv, name string) modface.FuncSignature {
+		return modface.FuncSignature{Name: name, Receiver: modface.Type{Name: recv, IsPointer: true}}
+	}
+	pkg := func(faces ...modface.FuncSignature) modface.PackageInterface {
+		pi := make(modface.PackageInterface)
+		for _, face := range faces {
+			pi[face.ID()] = face
+		}
+		return pi
+	}
+	return &modface.Module{
+		Path: modpath,
+		Packages: map[string]modface.PackageInterface{
+			modpath:          pkg(fn("Root")),
+			modpath + "/foo": pkg(fn("Foo"), fn("Other"), method("T", "Len"), method("U", "Len")),
+		},
+	}
+}
+
+// writeModule writes a module with the given files into a temporary directory.
+func writeModule(t *testing.T, modpath string, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "usage-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	files["go.mod"] = fmt.Sprintf("module %s\n\ngo 1.14\n", modpath)
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// describe returns a short description of each reference for comparison.
+func describe(refs []Reference) []string {
+	descs := []string{}
+	for _, ref := range refs {
+		desc := fmt.Sprintf("%s:%d %s %s", ref.Pos.Filename, ref.Pos.Line, ref.Package, ref.ID)
+		if ref.Inferred {
+			desc += " inferred"
+		}
+		if ref.Internal {
+			desc += " internal"
+		}
+		descs = append(descs, desc)
+	}
+	return descs
+}
+
+func TestScan(t *testing.T) {
+	const target = "example.com/target"
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "qualified",
+			files: map[string]string{"main.go": `package main
+
+import "example.com/target/foo"
+
+func main() {
+	foo.Foo()
+	foo.Missing()
+	_ = foo.Other
+}
+`},
+			want: []string{
+				"main.go:6 example.com/target/foo .Foo",
+				"main.go:8 example.com/target/foo .Other",
+			},
+		},
+		{
+			name: "renamed import",
+			files: map[string]string{"main.go": `package main
+
+import bar "example.com/target/foo"
+
+func main() {
+	bar.Foo()
+}
+`},
+			want: []string{"main.go:6 example.com/target/foo .Foo"},
+		},
+		{
+			name: "shadowed by local",
+			files: map[string]string{"main.go": `package main
+
+import "example.com/target/foo"
+
+type local struct{}
+
+func (local) Foo() {}
+
+func main() {
+	foo := local{}
+	foo.Foo()
+}
+
+func param(foo local) {
+	foo.Foo()
+}
+`},
+			want: []string{},
+		},
+		{
+			name: "dot import",
+			files: map[string]string{"main.go": `package main
+
+import . "example.com/target/foo"
+
+func main() {
+	Foo()
+	Other := 1
+	_ = Other
+}
+`},
+			want: []string{"main.go:6 example.com/target/foo .Foo"},
+		},
+		{
+			name: "major version suffix",
+			files: map[string]string{"main.go": `package main
+
+import "example.com/target/v2"
+
+func main() {
+	target.Root()
+}
+`},
+			want: []string{"main.go:6 example.com/target/v2 .Root"},
+		},
+		{
+			name: "composite literal keys",
+			files: map[string]string{"main.go": `package main
+
+import . "example.com/target/foo"
+
+type options struct {
+	Foo   func()
+	Other int
+}
+
+var opts = options{Foo: Foo, Other: 1}
+`},
+			want: []string{"main.go:10 example.com/target/foo .Foo"},
+		},
+		{
+			name: "methods by name",
+			files: map[string]string{"main.go": `package main
+
+import "example.com/target/foo"
+
+func count(t *foo.T, s []string) int {
+	return t.Len() + len(s)
+}
+`},
+			want: []string{
+				"main.go:6 example.com/target/foo T.Len inferred",
+				"main.go:6 example.com/target/foo U.Len inferred",
+			},
+		},
+		{
+			name: "methods of packages not imported",
+			files: map[string]string{"main.go": `package main
+
+type list []string
+
+func (l list) Len() int { return len(l) }
+
+func main() {
+	_ = list{}.Len()
+}
+`},
+			want: []string{},
+		},
+		{
+			name: "ignored directories and unparsable files",
+			files: map[string]string{
+				"main.go": `package main
+
+import "example.com/target/foo"
+
+func main() {
+	foo.Foo()
+}
+`,
+				"broken.go":        "package main\n\nimport \"example.com/target/foo\"\n\nfunc broken( {\n\tfoo.Foo()\n",
+				"testdata/data.go": "package data\n\nimport \"example.com/target/foo\"\n\nvar _ = foo.Foo\n",
+				"_scratch/old.go":  "package old\n\nimport \"example.com/target/foo\"\n\nvar _ = foo.Foo\n",
+				"pkg/_ignored.go":  "package pkg\n\nimport \"example.com/target/foo\"\n\nvar _ = foo.Foo\n",
+				"pkg/pkg_test.go":  "package pkg_test\n\nimport \"example.com/target/foo\"\n\nvar _ = foo.Other\n",
+				"nested/go.mod":    "module example.com/nested\n",
+				"nested/nested.go": "package nested\n\nimport \"example.com/target/foo\"\n\nvar _ = foo.Foo\n",
+			},
+			want: []string{
+				"main.go:6 example.com/target/foo .Foo",
+				"pkg/pkg_test.go:5 example.com/target/foo .Other",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modpath := target
+			if tt.name == "major version suffix" {
+				modpath = target + "/v2"
+			}
+			dir := writeModule(t, "example.com/consumer", tt.files)
+			refs, err := Scan(context.Background(), dir, testTarget(modpath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describe(refs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanInternal(t *testing.T) {
+	const target = "example.com/target"
+	dir := writeModule(t, target, map[string]string{
+		"root.go": "package target\n\nfunc Root() {}\n\nfunc use() {\n\tRoot()\n}\n",
+		"foo/foo.go": `package foo
+
+func Foo() {}
+
+func Other() {
+	Foo()
+	var Foo = 1
+	_ = Foo
+}
+`,
+		"foo/foo_test.go": "package foo_test\n\nimport \"example.com/target/foo\"\n\nfunc Foo() {\n\tfoo.Foo()\n}\n",
+	})
+
+	refs, err := Scan(context.Background(), dir, testTarget(target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"foo/foo.go:6 example.com/target/foo .Foo internal",
+		"foo/foo_test.go:6 example.com/target/foo .Foo internal",
+		"root.go:6 example.com/target .Root internal",
+	}
+	if got := describe(refs); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %q, want %q", got, want)
+	}
+}
+
+func TestScanCanceled(t *testing.T) {
+	const target = "example.com/target"
+	dir := writeModule(t, "example.com/consumer", map[string]string{
+		"main.go": "package main\n\nimport \"example.com/target\"\n\nfunc main() {\n\ttarget.Root()\n}\n",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Scan(ctx, dir, testTarget(target)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Scan() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{"example.com/foo", "foo"},
+		{"example.com/foo/v2", "foo"},
+		{"example.com/foo/v2/bar", "bar"},
+		{"example.com/go-yaml", "go_yaml"},
+		{"v2", "v2"},
+		{"example.com/vx", "vx"},
+	}
+	for _, tt := range tests {
+		if got := packageName(tt.importPath); got != tt.want {
+			t.Errorf("packageName(%q) = %q, want %q", tt.importPath, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package vcs
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// Git is a git repository.
+type Git struct {
+	Root   string // root directory of the repository
+	Prefix string // slash-separated path of the module within the repository
+}
+
+// Name returns "git".
+func (g *Git) Name() string {
+	return "git"
+}
+
+// Command returns a git command to run against the repository.
+func (g *Git) Command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, "git", append([]string{"-C", g.Root}, args...)...)
+}
+
+// Run runs a command returned by Command and returns its trimmed output.
+func (g *Git) Run(ctx context.Context, cmd *exec.Cmd) (string, error) {
+	out, err := output(ctx, cmd)
+	return strings.TrimSpace(out), err
+}
+
+// Output runs a git command against the repository and returns its trimmed output.
+func (g *Git) Output(ctx context.Context, args ...string) (string, error) {
+	return g.Run(ctx, g.Command(ctx, args...))
+}
+
+// Test runs a git command against the repository which reports a condition by its exit status.
+// An exit status of 1 is reported as false rather than as an error.
+func (g *Git) Test(ctx context.Context, args ...string) (bool, error) {
+	_, err := g.Output(ctx, args...)
+	if e, ok := err.(*Error); ok {
+		if ee, ok := e.Err.(*exec.ExitError); ok && ee.ExitCode() == 1 {
+			return false, nil
+		}
+	}
+	return err == nil, err
+}
+
+// Tags returns the names of all tags in the repository.
+func (g *Git) Tags(ctx context.Context) ([]string, error) {
+	out, err := output(ctx, g.Command(ctx, "tag", "--list"))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// TagPrefix returns the path of the module within the repository followed by a slash, if the
+// module is not at the repository root.
+func (g *Git) TagPrefix() string {
+	if g.Prefix == "" {
+		return ""
+	}
+	return g.Prefix + "/"
+}
+
+// Export writes the module tree at a revision into dir.
+// Files that are not committed at the revision are not written.
+func (g *Git) Export(ctx context.Context, rev, dir string) error {
+	args := []string{"archive", "--format=tar", rev}
+	if g.Prefix != "" {
+		args = append(args, "--", g.Prefix)
+	}
+	return exportArchive(ctx, g.Command(ctx, args...), g.Prefix, dir)
+}
+
+// Resolve returns the commit hash of a revision.
+func (g *Git) Resolve(ctx context.Context, rev string) (string, error) {
+	out, err := output(ctx, g.Command(ctx, "rev-parse", "--verify", "--quiet", rev+"^{commit}"))
+	return strings.TrimSpace(out), err
+}
+
+// Revision returns the commit hash of HEAD.
+func (g *Git) Revision(ctx context.Context) (string, error) {
+	out, err := output(ctx, g.Command(ctx, "rev-parse", "HEAD"))
+	return strings.TrimSpace(out), err
+}
+
+// Dirty reports whether tracked files of the working tree have uncommitted changes.
+func (g *Git) Dirty(ctx context.Context) (bool, error) {
+	out, err := output(ctx, g.Command(ctx, "status", "--porcelain", "--untracked-files=no"))
+	return strings.TrimSpace(out) != "", err
+}
+
+// Branch returns the name of the branch checked out in the working tree, or HEAD if it is detached.
+func (g *Git) Branch(ctx context.Context) (string, error) {
+	return g.Output(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// TagsAt returns the names of the tags pointing at a revision.
+func (g *Git) TagsAt(ctx context.Context, rev string) ([]string, error) {
+	out, err := g.Output(ctx, "tag", "--points-at", rev)
+	return strings.Fields(out), err
+}
+
+// HasTag reports whether a tag exists in the repository.
+func (g *Git) HasTag(ctx context.Context, tag string) (bool, error) {
+	return g.Test(ctx, "rev-parse", "--quiet", "--verify", "refs/tags/"+tag)
+}
+
+// RemoteHasTag reports whether a tag exists on a remote of the repository.
+func (g *Git) RemoteHasTag(ctx context.Context, remote, tag string) (bool, error) {
+	out, err := g.Output(ctx, "ls-remote", "--tags", remote, "refs/tags/"+tag)
+	return out != "", err
+}
+
+// IsAncestor reports whether the ancestor revision is an ancestor of rev.
+func (g *Git) IsAncestor(ctx context.Context, ancestor, rev string) (bool, error) {
+	return g.Test(ctx, "merge-base", "--is-ancestor", ancestor, rev)
+}
+
+// DefaultBranch returns the branch that the HEAD of a remote refers to, as of the last fetch.
+// If the remote HEAD is not known, main or master is returned if the repository has that branch.
+// An empty string is returned if the default branch cannot be determined.
+func (g *Git) DefaultBranch(ctx context.Context, remote string) (string, error) {
+	if remote != "" {
+		ref, err := g.Output(ctx, "symbolic-ref", "--quiet", "--short",
+			"refs/remotes/"+remote+"/HEAD")
+		if err == nil && strings.HasPrefix(ref, remote+"/") {
+			return strings.TrimPrefix(ref, remote+"/"), nil
+		} else if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+	}
+	for _, branch := range []string{"main", "master"} {
+		found, err := g.Test(ctx, "rev-parse", "--quiet", "--verify", "refs/heads/"+branch)
+		if err != nil {
+			return "", err
+		} else if found {
+			return branch, nil
+		}
+	}
+	return "", nil
+}
+
+// DefaultRemote returns the upstream remote of branch, or origin if the branch has no upstream.
+// An empty string is returned if the repository has neither.
+func (g *Git) DefaultRemote(ctx context.Context, branch string) (string, error) {
+	upstream, err := g.Output(ctx, "config", "--get", "branch."+branch+".remote")
+	if err == nil && upstream != "" && upstream != "." {
+		return upstream, nil
+	}
+	remotes, err := g.Output(ctx, "remote")
+	if err != nil {
+		return "", err
+	}
+	for _, remote := range strings.Fields(remotes) {
+		if remote == "origin" {
+			return remote, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,73 @@
+package vcs
+
+import (
+	"context"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// Mercurial is a Mercurial repository.
+type Mercurial struct {
+	Root   string // root directory of the repository
+	Prefix string // slash-separated path of the module within the repository
+}
+
+// Name returns "hg".
+func (m *Mercurial) Name() string {
+	return "hg"
+}
+
+func (m *Mercurial) command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, "hg", append([]string{"--repository", m.Root}, args...)...)
+}
+
+// Tags returns the names of all tags in the repository, including the tip tag.
+func (m *Mercurial) Tags(ctx context.Context) ([]string, error) {
+	out, err := output(ctx, m.command(ctx, "tags", "--quiet"))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// TagPrefix returns the path of the module within the repository followed by a slash, if the
+// module is not at the repository root.
+func (m *Mercurial) TagPrefix() string {
+	if m.Prefix == "" {
+		return ""
+	}
+	return m.Prefix + "/"
+}
+
+// Export writes the module tree at a revision into dir.
+// Files that are not committed at the revision are not written.
+func (m *Mercurial) Export(ctx context.Context, rev, dir string) error {
+	// archive entries are placed under a fixed prefix directory, which is stripped with the module path
+	const archivePrefix = "archive"
+	args := []string{"--config", "ui.archivemeta=false", "archive", "--rev", rev,
+		"--type", "tar", "--prefix", archivePrefix, "--no-decode"}
+	if m.Prefix != "" {
+		args = append(args, "--include", "path:"+m.Prefix)
+	}
+	args = append(args, "-")
+	return exportArchive(ctx, m.command(ctx, args...), path.Join(archivePrefix, m.Prefix), dir)
+}
+
+// Resolve returns the changeset hash of a revision.
+func (m *Mercurial) Resolve(ctx context.Context, rev string) (string, error) {
+	out, err := output(ctx, m.command(ctx, "log", "--rev", rev, "--template", "{node}"))
+	return strings.TrimSpace(out), err
+}
+
+// Revision returns the changeset hash of the working directory parent.
+func (m *Mercurial) Revision(ctx context.Context) (string, error) {
+	out, err := output(ctx, m.command(ctx, "log", "--rev", ".", "--template", "{node}"))
+	return strings.TrimSpace(out), err
+}
+
+// Dirty reports whether tracked files of the working tree have uncommitted changes.
+func (m *Mercurial) Dirty(ctx context.Context) (bool, error) {
+	out, err := output(ctx, m.command(ctx, "status", "--modified", "--added", "--removed", "--deleted"))
+	return strings.TrimSpace(out) != "", err
+}
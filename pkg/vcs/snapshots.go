@@ -0,0 +1,94 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Snapshots is a directory holding a copy of the module tree at each revision, in a subdirectory
+// named by the revision. Each revision is also a tag. It stands in for a repository in tests.
+type Snapshots struct {
+	Dir string
+}
+
+// Name returns "snapshots".
+func (s *Snapshots) Name() string {
+	return "snapshots"
+}
+
+// Tags returns the names of all snapshots.
+func (s *Snapshots) Tags(ctx context.Context) ([]string, error) {
+	entries, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			tags = append(tags, entry.Name())
+		}
+	}
+	return tags, nil
+}
+
+// TagPrefix returns an empty string, as snapshots are named by version alone.
+func (s *Snapshots) TagPrefix() string {
+	return ""
+}
+
+// Export copies the snapshot of a revision into dir.
+func (s *Snapshots) Export(ctx context.Context, rev, dir string) error {
+	src := filepath.Join(s.Dir, rev)
+	if fi, err := os.Stat(src); err != nil || !fi.IsDir() || filepath.Dir(src) != filepath.Clean(s.Dir) {
+		return fmt.Errorf("unknown revision %s in %s", rev, s.Dir)
+	}
+
+	return filepath.Walk(src, func(filename string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, filename)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, rel)
+
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, 0755)
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(filename)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			f, err := os.Open(filename)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return writeFile(target, f, fi.Mode().Perm())
+		}
+	})
+}
+
+// Resolve returns an empty string, as snapshots may be replaced at any time.
+func (s *Snapshots) Resolve(ctx context.Context, rev string) (string, error) {
+	return "", nil
+}
+
+// Revision returns an empty string, as the working tree is not one of the snapshots.
+func (s *Snapshots) Revision(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+// Dirty reports true, as the working tree is not one of the snapshots.
+func (s *Snapshots) Dirty(ctx context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,204 @@
+// Package vcs provides access to the history of a module through the version control system
+// that the module is kept in.
+package vcs
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SnapshotDir is the directory of a module root which holds snapshots of the module by revision.
+const SnapshotDir = ".gover-snapshots"
+
+// VCS is a version control system holding the history of a module.
+type VCS interface {
+	// Name returns the name of the version control system.
+	Name() string
+	// Tags returns the names of all tags in the repository.
+	Tags(ctx context.Context) ([]string, error)
+	// TagPrefix returns the prefix of the version tags of the module, such as "sub/" for the
+	// module in the sub directory of a repository, or an empty string for a module at the root.
+	TagPrefix() string
+	// Export writes the module tree at a revision into dir, which must exist.
+	Export(ctx context.Context, rev, dir string) error
+	// Resolve returns the identifier of the commit that a revision refers to, or an empty string
+	// if the revision does not refer to a fixed commit.
+	Resolve(ctx context.Context, rev string) (string, error)
+	// Revision returns the identifier of the revision checked out in the working tree.
+	Revision(ctx context.Context) (string, error)
+	// Dirty reports whether tracked files of the working tree have uncommitted changes.
+	Dirty(ctx context.Context) (bool, error)
+}
+
+// Detect returns the version control system of the module at moddir. A git or Mercurial
+// repository is detected by a .git or .hg entry in the module root or one of its parents.
+// A module root with a SnapshotDir directory and no repository uses its snapshots.
+func Detect(moddir string) (VCS, error) {
+	moddir, err := filepath.Abs(moddir)
+	if err != nil {
+		return nil, err
+	}
+
+	for root := moddir; ; root = filepath.Dir(root) {
+		prefix, err := filepath.Rel(root, moddir)
+		if err != nil {
+			return nil, err
+		}
+		if prefix = filepath.ToSlash(prefix); prefix == "." {
+			prefix = ""
+		}
+
+		if exists(filepath.Join(root, ".git")) {
+			return &Git{Root: root, Prefix: prefix}, nil
+		} else if exists(filepath.Join(root, ".hg")) {
+			return &Mercurial{Root: root, Prefix: prefix}, nil
+		}
+
+		if filepath.Dir(root) == root {
+			break
+		}
+	}
+
+	if exists(filepath.Join(moddir, SnapshotDir)) {
+		return &Snapshots{Dir: filepath.Join(moddir, SnapshotDir)}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not in a git or mercurial repository", moddir)
+}
+
+func exists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+// Error is returned when a version control command fails.
+type Error struct {
+	Cmd    string // command line that was run
+	Stderr string // trimmed error output of the command, if it ran
+	Err    error  // underlying error running the command
+}
+
+func (e *Error) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("%s: %s", e.Cmd, e.Stderr)
+	}
+	return fmt.Sprintf("%s: %v", e.Cmd, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// output runs cmd and returns its output.
+func output(ctx context.Context, cmd *exec.Cmd) (string, error) {
+	out, err := cmd.Output()
+	switch v := err.(type) {
+	case nil:
+		return string(out), nil
+	case *exec.ExitError:
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", &Error{Cmd: cmd.String(), Stderr: strings.TrimSpace(string(v.Stderr)), Err: err}
+	default:
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", &Error{Cmd: cmd.String(), Err: err}
+	}
+}
+
+// exportArchive runs cmd, which writes a tar archive to its standard output, and extracts the
+// entries under strip into dir.
+func exportArchive(ctx context.Context, cmd *exec.Cmd, strip, dir string) error {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &Error{Cmd: cmd.String(), Err: err}
+	}
+
+	extractErr := extractTar(stdout, strip, dir)
+	io.Copy(ioutil.Discard, stdout)
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		} else if _, ok := err.(*exec.ExitError); ok {
+			return &Error{Cmd: cmd.String(), Stderr: strings.TrimSpace(stderr.String()), Err: err}
+		}
+		return &Error{Cmd: cmd.String(), Err: err}
+	}
+	return extractErr
+}
+
+// extractTar extracts the entries of a tar archive under strip into dir.
+func extractTar(r io.Reader, strip, dir string) error {
+	strip = strings.TrimSuffix(strip, "/")
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		name := path.Clean(hdr.Name)
+		if strip != "" {
+			if name != strip && !strings.HasPrefix(name, strip+"/") {
+				continue
+			}
+			name = strings.TrimPrefix(strings.TrimPrefix(name, strip), "/")
+		}
+		if name == "" || name == "." {
+			continue
+		} else if strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return fmt.Errorf("archive entry %s is outside of the archive root", hdr.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = writeFile(target, tr, os.FileMode(hdr.Mode).Perm())
+		case tar.TypeSymlink:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+				err = os.Symlink(hdr.Linkname, target)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeFile(filename string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,405 @@
+package vcs
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tempDir creates a temporary directory which is removed when the test finishes.
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "vcs-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// writeFiles writes files with the given contents, keyed by slash-separated path, under dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readFiles returns the contents of all files under dir, keyed by slash-separated path.
+func readFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.Walk(dir, func(filename string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, filename)
+		files[filepath.ToSlash(rel)] = string(content)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestDetect(t *testing.T) {
+	root := tempDir(t)
+	for _, dir := range []string{
+		"git/.git", "git/sub/mod",
+		"hg/.hg", "hg/mod",
+		"snap/" + SnapshotDir,
+		"none/mod",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		moddir string
+		want   VCS
+	}{
+		{"git", &Git{Root: filepath.Join(root, "git")}},
+		{"git/sub/mod", &Git{Root: filepath.Join(root, "git"), Prefix: "sub/mod"}},
+		{"hg", &Mercurial{Root: filepath.Join(root, "hg")}},
+		{"hg/mod", &Mercurial{Root: filepath.Join(root, "hg"), Prefix: "mod"}},
+		{"snap", &Snapshots{Dir: filepath.Join(root, "snap", SnapshotDir)}},
+	}
+	for _, tt := range tests {
+		got, err := Detect(filepath.Join(root, filepath.FromSlash(tt.moddir)))
+		if err != nil {
+			t.Errorf("Detect(%s) error: %v", tt.moddir, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Detect(%s) = %#v, want %#v", tt.moddir, got, tt.want)
+		}
+	}
+
+	// the temporary directory itself may be inside a repository, so only report what was found
+	if got, err := Detect(filepath.Join(root, "none", "mod")); err == nil {
+		if g, ok := got.(*Git); ok && strings.HasPrefix(g.Root, root) {
+			t.Errorf("Detect(none/mod) = %#v, want no repository under %s", got, root)
+		}
+	}
+}
+
+func TestTagPrefix(t *testing.T) {
+	tests := []struct {
+		v    VCS
+		want string
+	}{
+		{&Git{Root: "/repo"}, ""},
+		{&Git{Root: "/repo", Prefix: "sub/mod"}, "sub/mod/"},
+		{&Mercurial{Root: "/repo", Prefix: "mod"}, "mod/"},
+		{&Snapshots{Dir: "/repo/" + SnapshotDir}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.v.TagPrefix(); got != tt.want {
+			t.Errorf("%#v.TagPrefix() = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+// tarball returns a tar archive of the given entries, keyed by name. Names ending in a slash
+// are directories.
+func tarball(t *testing.T, entries ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range entries {
+		hdr := &tar.Header{Name: name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(name))}
+		if strings.HasSuffix(name, "/") {
+			hdr = &tar.Header{Name: name, Mode: 0755, Typeflag: tar.TypeDir}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractTar(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		strip   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "no prefix",
+			entries: []string{"go.mod", "pkg/", "pkg/a.go"},
+			want:    map[string]string{"go.mod": "go.mod", "pkg/a.go": "pkg/a.go"},
+		},
+		{
+			name:    "strip prefix",
+			entries: []string{"go.mod", "sub/", "sub/go.mod", "sub/pkg/a.go", "subway/b.go"},
+			strip:   "sub/",
+			want:    map[string]string{"go.mod": "sub/go.mod", "pkg/a.go": "sub/pkg/a.go"},
+		},
+		{
+			name:    "strip nested prefix",
+			entries: []string{"archive/a/b/go.mod", "archive/a/c.go"},
+			strip:   "archive/a/b",
+			want:    map[string]string{"go.mod": "archive/a/b/go.mod"},
+		},
+		{
+			name:    "parent traversal",
+			entries: []string{"go.mod", "../evil.go"},
+			wantErr: true,
+		},
+		{
+			name:    "parent traversal within prefix",
+			entries: []string{"sub/go.mod", "sub/../../evil.go"},
+			strip:   "sub",
+			want:    map[string]string{"go.mod": "sub/go.mod"},
+		},
+		{
+			name:    "absolute path",
+			entries: []string{"/etc/evil"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := tempDir(t)
+			dir := filepath.Join(parent, "out")
+			if err := os.Mkdir(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			err := extractTar(bytes.NewReader(tarball(t, tt.entries...)), tt.strip, dir)
+			if _, statErr := os.Stat(filepath.Join(parent, "evil.go")); statErr == nil {
+				t.Error("extractTar() wrote outside of dir")
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Error("extractTar() succeeded, want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := readFiles(t, dir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractTar() wrote %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportArchive(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not installed")
+	}
+	src := tempDir(t)
+	archive := filepath.Join(src, "archive.tar")
+	content := tarball(t, "root.go", "sub/go.mod", "sub/pkg/a.go")
+	if err := ioutil.WriteFile(archive, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	dir := tempDir(t)
+	if err := exportArchive(ctx, exec.CommandContext(ctx, "cat", archive), "sub", dir); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"go.mod": "sub/go.mod", "pkg/a.go": "sub/pkg/a.go"}
+	if got := readFiles(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("exportArchive() wrote %v, want %v", got, want)
+	}
+
+	// a failing command is reported with its error output
+	err := exportArchive(ctx, exec.CommandContext(ctx, "cat", filepath.Join(src, "missing.tar")), "", dir)
+	if e, ok := err.(*Error); !ok || e.Stderr == "" {
+		t.Errorf("exportArchive() with failing command error = %#v, want *Error with stderr", err)
+	}
+}
+
+func TestGitExport(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := tempDir(t)
+	writeFiles(t, root, map[string]string{
+		"go.mod":          "module example.com/root\n",
+		"sub/go.mod":      "module example.com/root/sub\n",
+		"sub/pkg/a.go":    "package pkg\n",
+		"subway/b.go":     "package subway\n",
+		"sub/untracked.x": "not committed",
+	})
+	g := &Git{Root: root}
+	ctx := context.Background()
+	env := []string{"GIT_AUTHOR_NAME=vcs", "GIT_AUTHOR_EMAIL=vcs@example.com",
+		"GIT_COMMITTER_NAME=vcs", "GIT_COMMITTER_EMAIL=vcs@example.com", "HOME=" + root}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "go.mod", "sub/go.mod", "sub/pkg/a.go", "subway/b.go"},
+		{"commit", "--quiet", "-m", "initial"},
+		{"tag", "sub/v1.0.0"},
+	} {
+		cmd := g.Command(ctx, args...)
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	dir := tempDir(t)
+	nested := &Git{Root: root, Prefix: "sub"}
+	if err := nested.Export(ctx, nested.TagPrefix()+"v1.0.0", dir); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"go.mod": "module example.com/root/sub\n", "pkg/a.go": "package pkg\n"}
+	if got := readFiles(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("Export() wrote %v, want %v", got, want)
+	}
+
+	tags, err := g.Tags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(tags, []string{"sub/v1.0.0"}) {
+		t.Errorf("Tags() = %v, want [sub/v1.0.0]", tags)
+	}
+
+	if exists, err := g.HasTag(ctx, "sub/v1.0.0"); err != nil || !exists {
+		t.Errorf("HasTag(sub/v1.0.0) = %v, %v, want true", exists, err)
+	}
+	if exists, err := g.HasTag(ctx, "v1.0.0"); err != nil || exists {
+		t.Errorf("HasTag(v1.0.0) = %v, %v, want false", exists, err)
+	}
+	if dirty, err := g.Dirty(ctx); err != nil || dirty {
+		t.Errorf("Dirty() = %v, %v, want false", dirty, err)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	dir := tempDir(t)
+	snapdir := filepath.Join(dir, SnapshotDir)
+	writeFiles(t, snapdir, map[string]string{
+		"v1.0.0/go.mod":     "module example.com/snap\n",
+		"v1.0.0/a.go":       "package snap\n",
+		"v1.1.0/go.mod":     "module example.com/snap\n",
+		"v1.1.0/pkg/b.go":   "package pkg\n",
+		"not-a-snapshot.md": "ignored",
+	})
+	s := &Snapshots{Dir: snapdir}
+	ctx := context.Background()
+
+	tags, err := s.Tags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(tags)
+	if want := []string{"v1.0.0", "v1.1.0"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("Tags() = %v, want %v", tags, want)
+	}
+
+	out := tempDir(t)
+	if err := s.Export(ctx, "v1.1.0", out); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"go.mod": "module example.com/snap\n", "pkg/b.go": "package pkg\n"}
+	if got := readFiles(t, out); !reflect.DeepEqual(got, want) {
+		t.Errorf("Export(v1.1.0) wrote %v, want %v", got, want)
+	}
+
+	for _, rev := range []string{"v2.0.0", "not-a-snapshot.md", "v1.0.0/..", "..", "../" + SnapshotDir} {
+		if err := s.Export(ctx, rev, tempDir(t)); err == nil {
+			t.Errorf("Export(%s) succeeded, want error", rev)
+		}
+	}
+
+	if commit, err := s.Resolve(ctx, "v1.0.0"); err != nil || commit != "" {
+		t.Errorf("Resolve() = %q, %v, want no commit", commit, err)
+	}
+	if dirty, err := s.Dirty(ctx); err != nil || !dirty {
+		t.Errorf("Dirty() = %v, %v, want true", dirty, err)
+	}
+}
+
+func TestGitContextError(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	g := &Git{Root: tempDir(t)}
+	if _, err := g.Tags(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Tags() with expired context error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := g.Export(ctx, "HEAD", tempDir(t)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Export() with expired context error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestGitDefaultBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := tempDir(t)
+	writeFiles(t, root, map[string]string{"go.mod": "module example.com/root\n"})
+	g := &Git{Root: root}
+	ctx := context.Background()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := g.Command(ctx, args...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=vcs", "GIT_AUTHOR_EMAIL=vcs@example.com",
+			"GIT_COMMITTER_NAME=vcs", "GIT_COMMITTER_EMAIL=vcs@example.com", "HOME="+root)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "--quiet")
+	run("checkout", "--quiet", "-b", "trunk")
+	run("add", "go.mod")
+	run("commit", "--quiet", "-m", "initial")
+
+	check := func(remote, want string) {
+		t.Helper()
+		if got, err := g.DefaultBranch(ctx, remote); err != nil || got != want {
+			t.Errorf("DefaultBranch(%q) = %q, %v, want %q", remote, got, err, want)
+		}
+	}
+	check("", "")
+	check("origin", "")
+	run("branch", "master")
+	check("origin", "master")
+	run("branch", "main")
+	check("origin", "main")
+	run("update-ref", "refs/remotes/origin/trunk", "HEAD")
+	run("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk")
+	check("origin", "trunk")
+	check("upstream", "main")
+}
@@ -0,0 +1,41 @@
+package versioning
+
+import (
+	"fmt"
+	"time"
+)
+
+// CalVer is a calendar versioning policy of the form vYYYY.MM.MICRO.
+// The micro version is incremented for any change within the same month and reset otherwise.
+// The level of change does not affect the version.
+//
+// Note that Go requires a /vYYYY module path suffix for such versions.
+type CalVer struct {
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Name returns the name of the policy.
+func (CalVer) Name() string {
+	return "calver"
+}
+
+// Initial returns the version to use for a module that has not been versioned yet.
+func (c CalVer) Initial() string {
+	now := Options{Now: c.Now}.now()
+	return fmt.Sprintf(vfmt, now.Year(), int(now.Month()), 0)
+}
+
+// Next returns the version following current for a change of the specified level.
+func (c CalVer) Next(current string, change Change) (string, error) {
+	v, err := parse(current)
+	if err != nil {
+		return "", err
+	}
+
+	now := Options{Now: c.Now}.now()
+	if v.major == now.Year() && v.minor == int(now.Month()) {
+		return v.nextPatch(), nil
+	}
+	return c.Initial(), nil
+}
@@ -0,0 +1,44 @@
+package versioning
+
+import "fmt"
+
+// GoDefault is the policy historically used by gover.
+// Breaking changes bump the major version, or the minor version for v0 modules.
+// Features bump the minor version, or the patch version for v0 modules.
+// Bugfixes always bump the patch version.
+type GoDefault struct{}
+
+// Name returns the name of the policy.
+func (GoDefault) Name() string {
+	return "go-default"
+}
+
+// Initial returns the version to use for a module that has not been versioned yet.
+func (GoDefault) Initial() string {
+	return "v0.1.0"
+}
+
+// Next returns the version following current for a change of the specified level.
+func (GoDefault) Next(current string, change Change) (string, error) {
+	v, err := parse(current)
+	if err != nil {
+		return "", err
+	}
+
+	switch change {
+	case Breaking:
+		if v.major == 0 {
+			return v.nextMinor(), nil
+		}
+		return v.nextMajor(), nil
+	case Feature:
+		if v.major == 0 {
+			return v.nextPatch(), nil
+		}
+		return v.nextMinor(), nil
+	case Bugfix:
+		return v.nextPatch(), nil
+	}
+
+	return "", fmt.Errorf("unexpected change type: %s", change)
+}
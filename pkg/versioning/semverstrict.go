@@ -0,0 +1,51 @@
+package versioning
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrBreakingNotAllowed is returned when a policy refuses to version a breaking change.
+var ErrBreakingNotAllowed = errors.New("breaking change not allowed")
+
+// SemverStrict is a policy that follows semantic versioning without special treatment of
+// features for v0 modules. Features always bump the minor version.
+// Breaking changes to v0 modules bump the minor version, but only if AllowBreaking is set.
+type SemverStrict struct {
+	AllowBreaking bool
+}
+
+// Name returns the name of the policy.
+func (SemverStrict) Name() string {
+	return "semver-strict"
+}
+
+// Initial returns the version to use for a module that has not been versioned yet.
+func (SemverStrict) Initial() string {
+	return "v0.1.0"
+}
+
+// Next returns the version following current for a change of the specified level.
+func (s SemverStrict) Next(current string, change Change) (string, error) {
+	v, err := parse(current)
+	if err != nil {
+		return "", err
+	}
+
+	switch change {
+	case Breaking:
+		if v.major == 0 {
+			if !s.AllowBreaking {
+				return "", fmt.Errorf("%w: %s is a v0 version", ErrBreakingNotAllowed, current)
+			}
+			return v.nextMinor(), nil
+		}
+		return v.nextMajor(), nil
+	case Feature:
+		return v.nextMinor(), nil
+	case Bugfix:
+		return v.nextPatch(), nil
+	}
+
+	return "", fmt.Errorf("unexpected change type: %s", change)
+}
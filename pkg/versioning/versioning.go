@@ -0,0 +1,177 @@
+// Package versioning defines policies for choosing the next version of a module based on the
+// level of change to its interface.
+package versioning
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// DefaultPolicy is the name of the policy used when none is specified.
+const DefaultPolicy = "go-default"
+
+// Change is the level of change between two versions of a module interface.
+type Change int
+
+const (
+	// Bugfix indicates that the module interface has not changed.
+	Bugfix Change = iota
+	// Feature indicates backwards compatible changes to the module interface.
+	Feature
+	// Breaking indicates backwards incompatible changes to the module interface.
+	Breaking
+)
+
+func (c Change) String() string {
+	switch c {
+	case Bugfix:
+		return "bugfix"
+	case Feature:
+		return "feature"
+	case Breaking:
+		return "breaking"
+	}
+	return fmt.Sprintf("Change(%d)", int(c))
+}
+
+// Difference is implemented by interface differences, such as modface.ModuleDifference.
+type Difference interface {
+	Any() bool
+	Breaking() bool
+}
+
+// Classify returns the level of change represented by a difference.
+func Classify(d Difference) Change {
+	if d.Breaking() {
+		return Breaking
+	} else if d.Any() {
+		return Feature
+	}
+	return Bugfix
+}
+
+// Policy determines versions for a module.
+type Policy interface {
+	// Name returns the name of the policy.
+	Name() string
+
+	// Initial returns the version to use for a module that has not been versioned yet.
+	Initial() string
+
+	// Next returns the version following current for a change of the specified level.
+	Next(current string, change Change) (string, error)
+}
+
+// Options configures a policy created with New.
+type Options struct {
+	// AllowBreaking permits breaking changes that a policy would otherwise refuse.
+	AllowBreaking bool
+
+	// Now returns the current time for date-based policies.
+	// If nil, time.Now is used.
+	Now func() time.Time
+}
+
+func (o Options) now() time.Time {
+	if o.Now == nil {
+		return time.Now()
+	}
+	return o.Now()
+}
+
+// Factory creates a policy from options.
+type Factory func(opts Options) Policy
+
+var factories = map[string]Factory{
+	"go-default": func(opts Options) Policy {
+		return GoDefault{}
+	},
+	"semver-strict": func(opts Options) Policy {
+		return SemverStrict{AllowBreaking: opts.AllowBreaking}
+	},
+	"calver": func(opts Options) Policy {
+		return CalVer{Now: opts.Now}
+	},
+}
+
+// Register makes a policy available by name to New.
+// Registering a name that already exists replaces the previous policy.
+func Register(name string, factory Factory) {
+	factories[name] = factory
+}
+
+// Names returns the names of all registered policies in sorted order.
+func Names() []string {
+	names := []string{}
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New returns the policy registered under name.
+func New(name string, opts Options) (Policy, error) {
+	factory, found := factories[name]
+	if !found {
+		return nil, fmt.Errorf("unknown versioning policy: %s", name)
+	}
+	return factory(opts), nil
+}
+
+const vfmt = "v%d.%d.%d"
+
+// version is a parsed semantic version.
+type version struct {
+	major, minor, patch int
+	// prerelease is set for versions such as v1.2.3-rc.1, which precede their release v1.2.3.
+	prerelease bool
+}
+
+// parse parses a semantic version. Build metadata is ignored.
+func parse(v string) (version, error) {
+	if !semver.IsValid(v) {
+		return version{}, fmt.Errorf("invalid semantic version: %s", v)
+	}
+	canonical := semver.Canonical(v)
+	pre := semver.Prerelease(canonical)
+
+	var parsed version
+	if _, err := fmt.Sscanf(strings.TrimSuffix(canonical, pre), vfmt,
+		&parsed.major, &parsed.minor, &parsed.patch); err != nil {
+		return version{}, fmt.Errorf("invalid semantic version: %s: %v", v, err)
+	}
+	parsed.prerelease = pre != ""
+	return parsed, nil
+}
+
+// nextPatch returns the version following v with a bugfix.
+// A pre-release is followed by its own release.
+func (v version) nextPatch() string {
+	if v.prerelease {
+		return fmt.Sprintf(vfmt, v.major, v.minor, v.patch)
+	}
+	return fmt.Sprintf(vfmt, v.major, v.minor, v.patch+1)
+}
+
+// nextMinor returns the version following v with a new minor version.
+// A pre-release of a minor version, such as v1.3.0-rc.1, is followed by its own release.
+func (v version) nextMinor() string {
+	if v.prerelease && v.patch == 0 {
+		return fmt.Sprintf(vfmt, v.major, v.minor, 0)
+	}
+	return fmt.Sprintf(vfmt, v.major, v.minor+1, 0)
+}
+
+// nextMajor returns the version following v with a new major version.
+// A pre-release of a major version, such as v2.0.0-rc.1, is followed by its own release.
+func (v version) nextMajor() string {
+	if v.prerelease && v.minor == 0 && v.patch == 0 {
+		return fmt.Sprintf(vfmt, v.major, 0, 0)
+	}
+	return fmt.Sprintf(vfmt, v.major+1, 0, 0)
+}
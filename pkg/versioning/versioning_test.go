@@ -0,0 +1,101 @@
+package versioning
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	now := func() time.Time { return time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		policy  Policy
+		current string
+		change  Change
+		want    string
+		wantErr error
+	}{
+		// go-default
+		{GoDefault{}, "v0.1.0", Bugfix, "v0.1.1", nil},
+		{GoDefault{}, "v0.1.0", Feature, "v0.1.1", nil},
+		{GoDefault{}, "v0.1.3", Breaking, "v0.2.0", nil},
+		{GoDefault{}, "v1.2.3", Bugfix, "v1.2.4", nil},
+		{GoDefault{}, "v1.2.3", Feature, "v1.3.0", nil},
+		{GoDefault{}, "v1.2.3", Breaking, "v2.0.0", nil},
+		{GoDefault{}, "v1.2", Bugfix, "v1.2.1", nil},
+		{GoDefault{}, "v1.2.3+meta", Bugfix, "v1.2.4", nil},
+		{GoDefault{}, "v1.2.3-rc.1", Bugfix, "v1.2.3", nil},
+		{GoDefault{}, "v1.2.3-rc.1", Feature, "v1.3.0", nil},
+		{GoDefault{}, "v1.3.0-rc.1", Feature, "v1.3.0", nil},
+		{GoDefault{}, "v1.3.0-rc.1", Breaking, "v2.0.0", nil},
+		{GoDefault{}, "v2.0.0-beta", Breaking, "v2.0.0", nil},
+		{GoDefault{}, "v0.2.0-rc.1", Breaking, "v0.2.0", nil},
+
+		// semver-strict
+		{SemverStrict{}, "v0.1.0", Bugfix, "v0.1.1", nil},
+		{SemverStrict{}, "v0.1.0", Feature, "v0.2.0", nil},
+		{SemverStrict{}, "v0.1.0", Breaking, "", ErrBreakingNotAllowed},
+		{SemverStrict{AllowBreaking: true}, "v0.1.0", Breaking, "v0.2.0", nil},
+		{SemverStrict{}, "v1.2.3", Breaking, "v2.0.0", nil},
+		{SemverStrict{AllowBreaking: true}, "v1.2.3", Breaking, "v2.0.0", nil},
+		{SemverStrict{}, "v1.2.3", Feature, "v1.3.0", nil},
+		{SemverStrict{}, "v1.2.3-rc.1", Bugfix, "v1.2.3", nil},
+
+		// calver
+		{CalVer{Now: now}, "v2024.3.0", Bugfix, "v2024.3.1", nil},
+		{CalVer{Now: now}, "v2024.3.4", Breaking, "v2024.3.5", nil},
+		{CalVer{Now: now}, "v2024.2.7", Feature, "v2024.3.0", nil},
+		{CalVer{Now: now}, "v2023.3.1", Bugfix, "v2024.3.0", nil},
+		{CalVer{Now: now}, "v2024.3.2-rc.1", Bugfix, "v2024.3.2", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.policy.Next(tt.current, tt.change)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s.Next(%s, %s) error = %v, want %v", tt.policy.Name(), tt.current, tt.change,
+					err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s.Next(%s, %s) error = %v", tt.policy.Name(), tt.current, tt.change, err)
+		} else if got != tt.want {
+			t.Errorf("%s.Next(%s, %s) = %s, want %s", tt.policy.Name(), tt.current, tt.change,
+				got, tt.want)
+		}
+	}
+}
+
+func TestNextInvalid(t *testing.T) {
+	for _, policy := range []Policy{GoDefault{}, SemverStrict{}, CalVer{}} {
+		for _, current := range []string{"", "1.2.3", "v1.2.3.4", "latest"} {
+			if got, err := policy.Next(current, Bugfix); err == nil {
+				t.Errorf("%s.Next(%q) = %s, want error", policy.Name(), current, got)
+			}
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range Names() {
+		policy, err := New(name, Options{})
+		if err != nil {
+			t.Fatal(err)
+		} else if policy.Name() != name {
+			t.Errorf("New(%s).Name() = %s", name, policy.Name())
+		}
+	}
+
+	policy, err := New("semver-strict", Options{AllowBreaking: true})
+	if err != nil {
+		t.Fatal(err)
+	} else if next, err := policy.Next("v0.3.1", Breaking); err != nil || next != "v0.4.0" {
+		t.Errorf("semver-strict with AllowBreaking Next(v0.3.1, breaking) = %s, %v", next, err)
+	}
+
+	if _, err := New("no-such-policy", Options{}); err == nil {
+		t.Error("New(no-such-policy) succeeded, want error")
+	}
+}
@@ -0,0 +1,122 @@
+// Package versions discovers the released versions of a module, either from the version tags of
+// its repository or from the list endpoint of a module proxy.
+package versions
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dgravesa/gover/pkg/vcs"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// DefaultProxy is the module proxy used when GOPROXY does not name one.
+const DefaultProxy = "https://proxy.golang.org"
+
+// Options filters the versions that are discovered.
+type Options struct {
+	// Prefix selects the tags of a nested module, such as "sub/" for tags like "sub/v1.0.0".
+	// The prefix is trimmed from the versions that are returned.
+	Prefix string
+	// ExcludePrerelease leaves out versions with a pre-release suffix, such as v1.0.0-rc.1.
+	ExcludePrerelease bool
+	// Merged selects only the tags reachable from a commit. It is ignored by Proxy.
+	Merged string
+}
+
+// pseudoVersionRE matches pseudo-versions, which name untagged commits rather than releases.
+var pseudoVersionRE = regexp.MustCompile(`^v[0-9]+\.(0\.0-|\d+\.\d+-([^+]*\.)?0\.)\d{14}-[A-Za-z0-9]+(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// Filter returns the valid semantic versions among tags that match the options, sorted in
+// ascending semver order. Pseudo-versions and versions with build metadata are left out.
+func Filter(tags []string, opts Options) []string {
+	versions := []string{}
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, opts.Prefix) {
+			continue
+		}
+		version := strings.TrimPrefix(tag, opts.Prefix)
+		if !semver.IsValid(version) || semver.Build(version) != "" || pseudoVersionRE.MatchString(version) {
+			continue
+		} else if opts.ExcludePrerelease && semver.Prerelease(version) != "" {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	Sort(versions)
+	return versions
+}
+
+// Sort sorts versions in ascending semver order. Versions which are equal in precedence keep
+// their lexical order, so that the result does not depend on the order of the input.
+func Sort(versions []string) {
+	sort.Slice(versions, func(i, j int) bool {
+		if c := semver.Compare(versions[i], versions[j]); c != 0 {
+			return c < 0
+		}
+		return versions[i] < versions[j]
+	})
+}
+
+// Git returns the versions tagged in the git repository at dir.
+func Git(ctx context.Context, dir string, opts Options) ([]string, error) {
+	args := []string{"tag", "--list"}
+	if opts.Merged != "" {
+		args = append(args, "--merged", opts.Merged)
+	}
+	out, err := (&vcs.Git{Root: dir}).Output(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return Filter(strings.Fields(out), opts), nil
+}
+
+// Proxy returns the versions of a module listed by the module proxy at proxyURL.
+func Proxy(ctx context.Context, proxyURL, modpath string, opts Options) ([]string, error) {
+	escaped, err := module.EscapePath(modpath)
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(proxyURL, "/") + "/" + escaped + "/@v/list"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	// the list endpoint reports versions without the tag prefix of nested modules
+	opts.Prefix = ""
+	return Filter(strings.Fields(string(body)), opts), nil
+}
+
+// ProxyURL returns the first module proxy named by a GOPROXY setting, or DefaultProxy if the
+// setting is empty. An empty string is returned if the setting does not name any proxy.
+func ProxyURL(goproxy string) string {
+	if goproxy == "" {
+		return DefaultProxy
+	}
+	for _, entry := range strings.FieldsFunc(goproxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		if entry != "direct" && entry != "off" {
+			return entry
+		}
+	}
+	return ""
+}
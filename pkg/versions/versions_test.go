@@ -0,0 +1,145 @@
+package versions
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	tags := []string{
+		"v1.10.0", "v1.2.0", "v1.2.0-rc.1", "v0.1.0", "latest", "1.0.0", "v2.0.0+meta",
+		"v0.0.0-20190513183733-4bf6d317e70e", "sub/v0.2.0", "sub/v0.1.0", "sub/v0.3.0-beta",
+	}
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{"all", Options{}, []string{"v0.1.0", "v1.2.0-rc.1", "v1.2.0", "v1.10.0"}},
+		{"exclude prerelease", Options{ExcludePrerelease: true}, []string{"v0.1.0", "v1.2.0", "v1.10.0"}},
+		{"prefix", Options{Prefix: "sub/"}, []string{"v0.1.0", "v0.2.0", "v0.3.0-beta"}},
+		{"prefix exclude prerelease", Options{Prefix: "sub/", ExcludePrerelease: true},
+			[]string{"v0.1.0", "v0.2.0"}},
+		{"no match", Options{Prefix: "other/"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Filter(tags, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"v1.0.0", "v0.9.0", "v1.0", "v1.0.0-alpha", "v1", "v0.10.0"}
+	Sort(versions)
+	want := []string{"v0.9.0", "v0.10.0", "v1.0.0-alpha", "v1", "v1.0", "v1.0.0"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("Sort() = %v, want %v", versions, want)
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	tests := []struct {
+		goproxy, want string
+	}{
+		{"", DefaultProxy},
+		{"https://goproxy.io,direct", "https://goproxy.io"},
+		{"direct|https://corp.example.com", "https://corp.example.com"},
+		{"off", ""},
+		{"direct", ""},
+	}
+	for _, tt := range tests {
+		if got := ProxyURL(tt.goproxy); got != tt.want {
+			t.Errorf("ProxyURL(%q) = %q, want %q", tt.goproxy, got, tt.want)
+		}
+	}
+}
+
+func TestProxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/!example/mod/@v/list":
+			w.Write([]byte("v1.1.0\nv1.0.0\nv1.1.0-rc.1\nv0.0.0-20200101000000-abcdefabcdef\n"))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	got, err := Proxy(ctx, srv.URL+"/", "github.com/Example/mod", Options{ExcludePrerelease: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v1.0.0", "v1.1.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Proxy() = %v, want %v", got, want)
+	}
+
+	if _, err := Proxy(ctx, srv.URL, "github.com/example/missing", Options{}); err == nil {
+		t.Error("Proxy() of missing module succeeded, want error")
+	}
+}
+
+func TestGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir, err := ioutil.TempDir("", "versions-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "first")
+	git("tag", "v0.1.0")
+	git("tag", "sub/v0.1.0")
+	git("branch", "release")
+	git("commit", "-q", "--allow-empty", "-m", "second")
+	git("tag", "v0.10.0")
+	git("tag", "v0.2.0")
+	git("tag", "not-a-version")
+
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{"all", Options{}, []string{"v0.1.0", "v0.2.0", "v0.10.0"}},
+		{"merged", Options{Merged: "release"}, []string{"v0.1.0"}},
+		{"prefix", Options{Prefix: "sub/"}, []string{"v0.1.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Git(ctx, dir, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Git() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Git(ctx, dir, Options{Merged: "no-such-branch"}); err == nil {
+		t.Error("Git() with unknown merged commit succeeded, want error")
+	}
+}